@@ -1,6 +1,9 @@
 package ast
 
+import "github.com/menxqk/my-interpreter/token"
+
 type Node interface {
+	Position() token.Position
 	Literal() string
 	String() string
 	DebugString() string
@@ -20,6 +23,12 @@ type Program struct {
 	Statements []Statement
 }
 
+func (p *Program) Position() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Position()
+	}
+	return token.Position{}
+}
 func (p *Program) Literal() string     { return "" }
 func (p *Program) String() string      { return "" }
 func (p *Program) DebugString() string { return "" }
@@ -4,17 +4,21 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+
+	"github.com/menxqk/my-interpreter/token"
 )
 
 // IDENTIFIER
 type Identifier struct {
+	Pos         token.Position
 	Name        string
 	Type        string
 	TypeLiteral string
 }
 
-func (i *Identifier) expressionNode() {}
-func (i *Identifier) Literal() string { return i.Name }
+func (i *Identifier) expressionNode()          {}
+func (i *Identifier) Position() token.Position { return i.Pos }
+func (i *Identifier) Literal() string          { return i.Name }
 func (i *Identifier) String() string {
 	if i.TypeLiteral != "" {
 		return fmt.Sprintf("%s %s", i.TypeLiteral, i.Name)
@@ -30,12 +34,14 @@ func (i *Identifier) DebugString() string {
 
 // PREFIX EXPRESSION
 type PrefixExpression struct {
+	Pos        token.Position
 	Operator   string
 	Expression Expression
 }
 
-func (pe *PrefixExpression) expressionNode() {}
-func (pe *PrefixExpression) Literal() string { return pe.Operator }
+func (pe *PrefixExpression) expressionNode()          {}
+func (pe *PrefixExpression) Position() token.Position { return pe.Pos }
+func (pe *PrefixExpression) Literal() string          { return pe.Operator }
 func (pe *PrefixExpression) String() string {
 	if pe.Expression != nil {
 		return fmt.Sprintf("%s%s", pe.Operator, pe.Expression.String())
@@ -51,11 +57,13 @@ func (pe *PrefixExpression) DebugString() string {
 
 // GROUPED EXPRESSION
 type GroupedExpression struct {
+	Pos        token.Position
 	Expression Expression
 }
 
-func (ge *GroupedExpression) expressionNode() {}
-func (ge *GroupedExpression) Literal() string { return "(" }
+func (ge *GroupedExpression) expressionNode()          {}
+func (ge *GroupedExpression) Position() token.Position { return ge.Pos }
+func (ge *GroupedExpression) Literal() string          { return "(" }
 func (ge *GroupedExpression) String() string {
 	if ge.Expression != nil {
 		return fmt.Sprintf("(%s)", ge.Expression.String())
@@ -71,13 +79,15 @@ func (ge *GroupedExpression) DebugString() string {
 
 // INFIX EXPRESSION
 type InfixExpression struct {
+	Pos      token.Position
 	Left     Expression
 	Operator string
 	Right    Expression
 }
 
-func (ie *InfixExpression) expressionNode() {}
-func (ie *InfixExpression) Literal() string { return "()" }
+func (ie *InfixExpression) expressionNode()          {}
+func (ie *InfixExpression) Position() token.Position { return ie.Pos }
+func (ie *InfixExpression) Literal() string          { return "()" }
 func (ie *InfixExpression) String() string {
 	if ie.Left != nil && ie.Right != nil {
 		return fmt.Sprintf("(%s %s %s)", ie.Left.String(), ie.Operator, ie.Right.String())
@@ -93,13 +103,15 @@ func (ie *InfixExpression) DebugString() string {
 
 // IF EXPRESSION
 type IfExpression struct {
+	Pos         token.Position
 	Condition   Expression
 	Consequence *BlockStatement
 	Alternative *BlockStatement
 }
 
-func (ie *IfExpression) expressionNode() {}
-func (ie *IfExpression) Literal() string { return "if" }
+func (ie *IfExpression) expressionNode()          {}
+func (ie *IfExpression) Position() token.Position { return ie.Pos }
+func (ie *IfExpression) Literal() string          { return "if" }
 func (ie *IfExpression) String() string {
 	var out bytes.Buffer
 	if ie.Condition != nil {
@@ -130,13 +142,15 @@ func (ie *IfExpression) DebugString() string {
 
 // FUNCTION EXPRESSION
 type FunctionExpression struct {
+	Pos        token.Position
 	Identifier Identifier
 	Parameters []*Identifier
 	Body       *BlockStatement
 }
 
-func (fe *FunctionExpression) expressionNode() {}
-func (fe *FunctionExpression) Literal() string { return "func" }
+func (fe *FunctionExpression) expressionNode()          {}
+func (fe *FunctionExpression) Position() token.Position { return fe.Pos }
+func (fe *FunctionExpression) Literal() string          { return "func" }
 func (fe *FunctionExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString(fmt.Sprintf("%s %s(", fe.Identifier.TypeLiteral, fe.Identifier.Name))
@@ -169,15 +183,17 @@ func (fe *FunctionExpression) DebugString() string {
 
 // CALL EXPRESSION
 type CallExpression struct {
-	Identifier Identifier
-	Arguments  []Expression
+	Pos       token.Position
+	Function  Expression
+	Arguments []Expression
 }
 
-func (ce *CallExpression) expressionNode() {}
-func (ce *CallExpression) Literal() string { return "call" }
+func (ce *CallExpression) expressionNode()          {}
+func (ce *CallExpression) Position() token.Position { return ce.Pos }
+func (ce *CallExpression) Literal() string          { return "call" }
 func (ce *CallExpression) String() string {
 	var out bytes.Buffer
-	out.WriteString(fmt.Sprintf("%s(", ce.Identifier.String()))
+	out.WriteString(fmt.Sprintf("%s(", ce.Function.String()))
 	args := []string{}
 	for _, arg := range ce.Arguments {
 		args = append(args, arg.String())
@@ -188,7 +204,7 @@ func (ce *CallExpression) String() string {
 }
 func (ce *CallExpression) DebugString() string {
 	var out bytes.Buffer
-	out.WriteString(fmt.Sprintf("%s(", ce.Identifier.DebugString()))
+	out.WriteString(fmt.Sprintf("%s(", ce.Function.DebugString()))
 	args := []string{}
 	for _, arg := range ce.Arguments {
 		args = append(args, arg.DebugString())
@@ -199,46 +215,63 @@ func (ce *CallExpression) DebugString() string {
 	return out.String()
 }
 
-// ARRAY ELEMENT EXPRESSION
-type ArrayElementExpression struct {
-	Identifier Identifier
-	Index      int
-	Expression Expression
+// INDEX EXPRESSION
+type IndexExpression struct {
+	Pos   token.Position
+	Left  Expression
+	Index Expression
 }
 
-func (aee *ArrayElementExpression) expressionNode() {}
-func (aee *ArrayElementExpression) Literal() string { return "array element" }
-func (aee *ArrayElementExpression) String() string {
-	if aee.Expression != nil {
-		return fmt.Sprintf("%s[%d] = %s", aee.Identifier.String(), aee.Index, aee.Expression.String())
+func (ie *IndexExpression) expressionNode()          {}
+func (ie *IndexExpression) Position() token.Position { return ie.Pos }
+func (ie *IndexExpression) Literal() string          { return "index" }
+func (ie *IndexExpression) String() string {
+	if ie.Left == nil || ie.Index == nil {
+		return ""
 	}
-	return fmt.Sprintf("%s[%d]", aee.Identifier.String(), aee.Index)
+	return fmt.Sprintf("%s[%s]", ie.Left.String(), ie.Index.String())
 }
-func (aee *ArrayElementExpression) DebugString() string {
-	if aee.Expression != nil {
-		return fmt.Sprintf("%s[%d] = %s [%T]", aee.Identifier.String(), aee.Index, aee.Expression.DebugString(), aee)
+func (ie *IndexExpression) DebugString() string {
+	if ie.Left == nil || ie.Index == nil {
+		return ""
 	}
-	return fmt.Sprintf("%s[%d] [%T]", aee.Identifier.String(), aee.Index, aee)
+	return fmt.Sprintf("%s[%s] [%T]", ie.Left.DebugString(), ie.Index.DebugString(), ie)
 }
 
-// DICT ELEMENT EXPRESSION
-type DictElementExpression struct {
-	Identifier Identifier
-	Key        string
-	Expression Expression
+// SLICE EXPRESSION
+type SliceExpression struct {
+	Pos   token.Position
+	Left  Expression
+	Start Expression
+	End   Expression
 }
 
-func (dee *DictElementExpression) expressionNode() {}
-func (dee *DictElementExpression) Literal() string { return "dict element" }
-func (dee *DictElementExpression) String() string {
-	if dee.Expression != nil {
-		return fmt.Sprintf("%s[%s] = %s", dee.Identifier.String(), dee.Key, dee.Expression.String())
+func (se *SliceExpression) expressionNode()          {}
+func (se *SliceExpression) Position() token.Position { return se.Pos }
+func (se *SliceExpression) Literal() string          { return "slice" }
+func (se *SliceExpression) String() string {
+	if se.Left == nil {
+		return ""
+	}
+	start, end := "", ""
+	if se.Start != nil {
+		start = se.Start.String()
+	}
+	if se.End != nil {
+		end = se.End.String()
 	}
-	return fmt.Sprintf("%s", dee.Identifier.String())
+	return fmt.Sprintf("%s[%s:%s]", se.Left.String(), start, end)
 }
-func (dee *DictElementExpression) DebugString() string {
-	if dee.Expression != nil {
-		return fmt.Sprintf("%s[%s] = %s [%T]", dee.Identifier.String(), dee.Key, dee.Expression.String(), dee)
+func (se *SliceExpression) DebugString() string {
+	if se.Left == nil {
+		return ""
+	}
+	start, end := "", ""
+	if se.Start != nil {
+		start = se.Start.DebugString()
+	}
+	if se.End != nil {
+		end = se.End.DebugString()
 	}
-	return fmt.Sprintf("%s [%T]", dee.Identifier.String(), dee)
+	return fmt.Sprintf("%s[%s:%s] [%T]", se.Left.DebugString(), start, end, se)
 }
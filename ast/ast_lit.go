@@ -4,54 +4,66 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+
+	"github.com/menxqk/my-interpreter/token"
 )
 
 // INTEGER LITERAL
 type IntegerLiteral struct {
+	Pos   token.Position
 	Value int64
 }
 
-func (il *IntegerLiteral) expressionNode()     {}
-func (il *IntegerLiteral) Literal() string     { return fmt.Sprintf("%d", il.Value) }
-func (il *IntegerLiteral) String() string      { return fmt.Sprintf("%d", il.Value) }
-func (il *IntegerLiteral) DebugString() string { return fmt.Sprintf("%d [%T]", il.Value, il) }
+func (il *IntegerLiteral) expressionNode()          {}
+func (il *IntegerLiteral) Position() token.Position { return il.Pos }
+func (il *IntegerLiteral) Literal() string          { return fmt.Sprintf("%d", il.Value) }
+func (il *IntegerLiteral) String() string           { return fmt.Sprintf("%d", il.Value) }
+func (il *IntegerLiteral) DebugString() string      { return fmt.Sprintf("%d [%T]", il.Value, il) }
 
 // FLOAT LITERAL
 type FloatLiteral struct {
+	Pos   token.Position
 	Value float64
 }
 
-func (fl *FloatLiteral) expressionNode()     {}
-func (fl *FloatLiteral) Literal() string     { return fmt.Sprintf("%.6f", fl.Value) }
-func (fl *FloatLiteral) String() string      { return fmt.Sprintf("%.6f", fl.Value) }
-func (fl *FloatLiteral) DebugString() string { return fmt.Sprintf("%.6f [%T]", fl.Value, fl) }
+func (fl *FloatLiteral) expressionNode()          {}
+func (fl *FloatLiteral) Position() token.Position { return fl.Pos }
+func (fl *FloatLiteral) Literal() string          { return fmt.Sprintf("%.6f", fl.Value) }
+func (fl *FloatLiteral) String() string           { return fmt.Sprintf("%.6f", fl.Value) }
+func (fl *FloatLiteral) DebugString() string      { return fmt.Sprintf("%.6f [%T]", fl.Value, fl) }
 
 // CHAR LITERAL
 type CharLiteral struct {
+	Pos   token.Position
 	Value rune
 }
 
-func (cl *CharLiteral) expressionNode()     {}
-func (cl *CharLiteral) Literal() string     { return fmt.Sprintf("%s", string(cl.Value)) }
-func (cl *CharLiteral) String() string      { return fmt.Sprintf("'%s'", string(cl.Value)) }
-func (cl *CharLiteral) DebugString() string { return fmt.Sprintf("'%s' [%T]", string(cl.Value), cl) }
+func (cl *CharLiteral) expressionNode()          {}
+func (cl *CharLiteral) Position() token.Position { return cl.Pos }
+func (cl *CharLiteral) Literal() string          { return fmt.Sprintf("%s", string(cl.Value)) }
+func (cl *CharLiteral) String() string           { return fmt.Sprintf("'%s'", string(cl.Value)) }
+func (cl *CharLiteral) DebugString() string      { return fmt.Sprintf("'%s' [%T]", string(cl.Value), cl) }
 
 // STRING LITERAL
 type StringLiteral struct {
+	Pos   token.Position
 	Value string
 }
 
-func (sl *StringLiteral) expressionNode()     {}
-func (sl *StringLiteral) Literal() string     { return sl.Value }
-func (sl *StringLiteral) String() string      { return fmt.Sprintf("\"%s\"", sl.Value) }
-func (sl *StringLiteral) DebugString() string { return fmt.Sprintf("\"%s\" [%T]", sl.Value, sl) }
+func (sl *StringLiteral) expressionNode()          {}
+func (sl *StringLiteral) Position() token.Position { return sl.Pos }
+func (sl *StringLiteral) Literal() string          { return sl.Value }
+func (sl *StringLiteral) String() string           { return fmt.Sprintf("\"%s\"", sl.Value) }
+func (sl *StringLiteral) DebugString() string      { return fmt.Sprintf("\"%s\" [%T]", sl.Value, sl) }
 
 // BOOLEAN LITERAL
 type BooleanLiteral struct {
+	Pos   token.Position
 	Value bool
 }
 
-func (bl *BooleanLiteral) expressionNode() {}
+func (bl *BooleanLiteral) expressionNode()          {}
+func (bl *BooleanLiteral) Position() token.Position { return bl.Pos }
 func (bl *BooleanLiteral) Literal() string {
 	return fmt.Sprintf("%t", bl.Value)
 }
@@ -64,22 +76,26 @@ func (bl *BooleanLiteral) DebugString() string {
 
 // NULL LITERAL
 type NullLiteral struct {
+	Pos token.Position
 }
 
-func (nl *NullLiteral) expressionNode() {}
-func (nl *NullLiteral) Literal() string { return "null" }
-func (nl *NullLiteral) String() string  { return "null" }
+func (nl *NullLiteral) expressionNode()          {}
+func (nl *NullLiteral) Position() token.Position { return nl.Pos }
+func (nl *NullLiteral) Literal() string          { return "null" }
+func (nl *NullLiteral) String() string           { return "null" }
 func (nl *NullLiteral) DebugString() string {
 	return fmt.Sprintf("null [%T]", nl)
 }
 
 // ARRAY LITERAL
 type ArrayLiteral struct {
+	Pos      token.Position
 	Elements []Expression
 }
 
-func (al *ArrayLiteral) expressionNode() {}
-func (al *ArrayLiteral) Literal() string { return al.String() }
+func (al *ArrayLiteral) expressionNode()          {}
+func (al *ArrayLiteral) Position() token.Position { return al.Pos }
+func (al *ArrayLiteral) Literal() string          { return al.String() }
 func (al *ArrayLiteral) String() string {
 	var out bytes.Buffer
 	out.WriteString("[")
@@ -106,11 +122,13 @@ func (al *ArrayLiteral) DebugString() string {
 
 // DICT LITERAL
 type DictLiteral struct {
+	Pos      token.Position
 	Elements map[string]Expression
 }
 
-func (dl *DictLiteral) expressionNode() {}
-func (dl *DictLiteral) Literal() string { return dl.String() }
+func (dl *DictLiteral) expressionNode()          {}
+func (dl *DictLiteral) Position() token.Position { return dl.Pos }
+func (dl *DictLiteral) Literal() string          { return dl.String() }
 func (dl *DictLiteral) String() string {
 	var out bytes.Buffer
 	out.WriteString("{")
@@ -3,15 +3,20 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"strings"
+
+	"github.com/menxqk/my-interpreter/token"
 )
 
 // EXPRESSION STATEMENT
 type ExpressionStatement struct {
+	Pos        token.Position
 	Expression Expression
 }
 
-func (es *ExpressionStatement) statementNode()  {}
-func (es *ExpressionStatement) Literal() string { return "EXP_STMT" }
+func (es *ExpressionStatement) statementNode()           {}
+func (es *ExpressionStatement) Position() token.Position { return es.Pos }
+func (es *ExpressionStatement) Literal() string          { return "EXP_STMT" }
 func (es *ExpressionStatement) String() string {
 	if es.Expression != nil {
 		return fmt.Sprintf("%s;", es.Expression.String())
@@ -28,11 +33,13 @@ func (es *ExpressionStatement) DebugString() string {
 
 // BLOCK STATEMENT
 type BlockStatement struct {
+	Pos        token.Position
 	Statements []Statement
 }
 
-func (bs *BlockStatement) statementNode()  {}
-func (bs *BlockStatement) Literal() string { return "BLOCK_STMT" }
+func (bs *BlockStatement) statementNode()           {}
+func (bs *BlockStatement) Position() token.Position { return bs.Pos }
+func (bs *BlockStatement) Literal() string          { return "BLOCK_STMT" }
 func (bs *BlockStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString("{")
@@ -55,12 +62,14 @@ func (bs *BlockStatement) DebugString() string {
 
 // VARIABLE DECLARATION STATEMENT
 type VariableDeclarationStatement struct {
+	Pos        token.Position
 	Identifier Identifier
 	Expression Expression
 }
 
-func (vds *VariableDeclarationStatement) statementNode()  {}
-func (vds *VariableDeclarationStatement) Literal() string { return "VD_STMT" }
+func (vds *VariableDeclarationStatement) statementNode()           {}
+func (vds *VariableDeclarationStatement) Position() token.Position { return vds.Pos }
+func (vds *VariableDeclarationStatement) Literal() string          { return "VD_STMT" }
 func (vds *VariableDeclarationStatement) String() string {
 	if vds.Expression != nil {
 		return fmt.Sprintf("%s = %s;", vds.Identifier.String(), vds.Expression.String())
@@ -76,11 +85,13 @@ func (vds *VariableDeclarationStatement) DebugString() string {
 
 // FUNCTION DECLARATION STATEMENT
 type FunctionDeclarationStatement struct {
+	Pos      token.Position
 	Function Expression
 }
 
-func (fds *FunctionDeclarationStatement) statementNode()  {}
-func (fds *FunctionDeclarationStatement) Literal() string { return "FD_STMT" }
+func (fds *FunctionDeclarationStatement) statementNode()           {}
+func (fds *FunctionDeclarationStatement) Position() token.Position { return fds.Pos }
+func (fds *FunctionDeclarationStatement) Literal() string          { return "FD_STMT" }
 func (fds *FunctionDeclarationStatement) String() string {
 	if fds.Function != nil {
 		return fmt.Sprintf("%s", fds.Function.String())
@@ -96,63 +107,234 @@ func (fds *FunctionDeclarationStatement) DebugString() string {
 
 // ARRAY DECLARATION STATEMENT
 type ArrayDeclarationStatement struct {
+	Pos        token.Position
 	Identifier Identifier
 	Size       int
+	Dimensions int
 	Expression Expression
 }
 
-func (ads *ArrayDeclarationStatement) statementNode()  {}
-func (ads *ArrayDeclarationStatement) Literal() string { return "AD_STMT" }
+func (ads *ArrayDeclarationStatement) statementNode()           {}
+func (ads *ArrayDeclarationStatement) Position() token.Position { return ads.Pos }
+func (ads *ArrayDeclarationStatement) Literal() string          { return "AD_STMT" }
 func (ads *ArrayDeclarationStatement) String() string {
+	dims := ""
+	if ads.Dimensions > 1 {
+		dims = strings.Repeat("[]", ads.Dimensions-1)
+	}
 	if ads.Expression != nil {
-		return fmt.Sprintf("%s[%d] = %s;", ads.Identifier.String(), ads.Size, ads.Expression.String())
+		return fmt.Sprintf("%s[%d]%s = %s;", ads.Identifier.String(), ads.Size, dims, ads.Expression.String())
 	}
-	return fmt.Sprintf("%s[%d];", ads.Identifier.String(), ads.Size)
+	return fmt.Sprintf("%s[%d]%s;", ads.Identifier.String(), ads.Size, dims)
 }
 func (ads *ArrayDeclarationStatement) DebugString() string {
+	dims := ""
+	if ads.Dimensions > 1 {
+		dims = strings.Repeat("[]", ads.Dimensions-1)
+	}
 	if ads.Expression != nil {
-		return fmt.Sprintf("%s[%d] = %s [%T];", ads.Identifier.DebugString(), ads.Size, ads.Expression.DebugString(), ads)
+		return fmt.Sprintf("%s[%d]%s = %s [%T];", ads.Identifier.DebugString(), ads.Size, dims, ads.Expression.DebugString(), ads)
 	}
-	return fmt.Sprintf("%s[%d] [%T];", ads.Identifier.String(), ads.Size, ads)
+	return fmt.Sprintf("%s[%d]%s [%T];", ads.Identifier.String(), ads.Size, dims, ads)
 }
 
 // ASSIGNMENT STATEMENT
 type AssignmentStatement struct {
-	Identifier Identifier
+	Pos        token.Position
+	Left       Expression
+	Operator   string
 	Expression Expression
 }
 
-func (as *AssignmentStatement) statementNode()  {}
-func (as *AssignmentStatement) Literal() string { return "A_STMT" }
+func (as *AssignmentStatement) statementNode()           {}
+func (as *AssignmentStatement) Position() token.Position { return as.Pos }
+func (as *AssignmentStatement) Literal() string          { return "A_STMT" }
 func (as *AssignmentStatement) String() string {
+	if as.Left == nil {
+		return ""
+	}
 	if as.Expression != nil {
-		return fmt.Sprintf("%s = %s;", as.Identifier.String(), as.Expression.String())
+		return fmt.Sprintf("%s %s %s;", as.Left.String(), as.Operator, as.Expression.String())
 	}
-	return ""
+	return fmt.Sprintf("%s%s;", as.Left.String(), as.Operator)
 }
 func (as *AssignmentStatement) DebugString() string {
+	if as.Left == nil {
+		return ""
+	}
 	if as.Expression != nil {
-		return fmt.Sprintf("%s = %s [%T];", as.Identifier.DebugString(), as.Expression.DebugString(), as)
+		return fmt.Sprintf("%s %s %s [%T];", as.Left.DebugString(), as.Operator, as.Expression.DebugString(), as)
 	}
-	return ""
+	return fmt.Sprintf("%s%s [%T];", as.Left.DebugString(), as.Operator, as)
 }
 
 // RETURN STATEMENT
 type ReturnStatement struct {
+	Pos         token.Position
 	ReturnValue Expression
 }
 
-func (re *ReturnStatement) statementNode()  {}
-func (re *ReturnStatement) Literal() string { return "return" }
+func (re *ReturnStatement) statementNode()           {}
+func (re *ReturnStatement) Position() token.Position { return re.Pos }
+func (re *ReturnStatement) Literal() string          { return "return" }
 func (re *ReturnStatement) String() string {
 	if re.ReturnValue != nil {
 		return fmt.Sprintf("return %s;", re.ReturnValue.String())
 	}
-	return ""
+	return "return;"
 }
 func (re *ReturnStatement) DebugString() string {
 	if re.ReturnValue != nil {
 		return fmt.Sprintf("return %s [%T];", re.ReturnValue.DebugString(), re)
 	}
+	return fmt.Sprintf("return [%T];", re)
+}
+
+// WHILE STATEMENT
+type WhileStatement struct {
+	Pos       token.Position
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode()           {}
+func (ws *WhileStatement) Position() token.Position { return ws.Pos }
+func (ws *WhileStatement) Literal() string          { return "while" }
+func (ws *WhileStatement) String() string {
+	if ws.Condition != nil && ws.Body != nil {
+		return fmt.Sprintf("while (%s) %s", ws.Condition.String(), ws.Body.String())
+	}
 	return ""
 }
+func (ws *WhileStatement) DebugString() string {
+	if ws.Condition != nil && ws.Body != nil {
+		return fmt.Sprintf("while (%s) %s [%T]", ws.Condition.DebugString(), ws.Body.DebugString(), ws)
+	}
+	return ""
+}
+
+// FOR STATEMENT
+type ForStatement struct {
+	Pos       token.Position
+	Init      Statement
+	Condition Expression
+	Post      Statement
+	Body      *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()           {}
+func (fs *ForStatement) Position() token.Position { return fs.Pos }
+func (fs *ForStatement) Literal() string          { return "for" }
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("for (")
+	if fs.Init != nil {
+		out.WriteString(strings.TrimSuffix(fs.Init.String(), ";"))
+	}
+	out.WriteString("; ")
+	if fs.Condition != nil {
+		out.WriteString(fs.Condition.String())
+	}
+	out.WriteString("; ")
+	if fs.Post != nil {
+		out.WriteString(strings.TrimSuffix(fs.Post.String(), ";"))
+	}
+	out.WriteString(")")
+	if fs.Body != nil {
+		out.WriteString(fmt.Sprintf(" %s", fs.Body.String()))
+	}
+	return out.String()
+}
+func (fs *ForStatement) DebugString() string {
+	var out bytes.Buffer
+	out.WriteString("for (")
+	if fs.Init != nil {
+		out.WriteString(strings.TrimSuffix(fs.Init.DebugString(), ";"))
+	}
+	out.WriteString("; ")
+	if fs.Condition != nil {
+		out.WriteString(fs.Condition.DebugString())
+	}
+	out.WriteString("; ")
+	if fs.Post != nil {
+		out.WriteString(strings.TrimSuffix(fs.Post.DebugString(), ";"))
+	}
+	out.WriteString(")")
+	if fs.Body != nil {
+		out.WriteString(fmt.Sprintf(" %s", fs.Body.DebugString()))
+	}
+	out.WriteString(fmt.Sprintf(" [%T]", fs))
+	return out.String()
+}
+
+// FOR IN STATEMENT
+type ForInStatement struct {
+	Pos      token.Position
+	Key      *Identifier
+	Value    *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fis *ForInStatement) statementNode()           {}
+func (fis *ForInStatement) Position() token.Position { return fis.Pos }
+func (fis *ForInStatement) Literal() string          { return "for" }
+func (fis *ForInStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("for (")
+	if fis.Key != nil {
+		out.WriteString(fmt.Sprintf("%s, ", fis.Key.String()))
+	}
+	if fis.Value != nil {
+		out.WriteString(fis.Value.String())
+	}
+	if fis.Iterable != nil {
+		out.WriteString(fmt.Sprintf(" in %s", fis.Iterable.String()))
+	}
+	out.WriteString(")")
+	if fis.Body != nil {
+		out.WriteString(fmt.Sprintf(" %s", fis.Body.String()))
+	}
+	return out.String()
+}
+func (fis *ForInStatement) DebugString() string {
+	var out bytes.Buffer
+	out.WriteString("for (")
+	if fis.Key != nil {
+		out.WriteString(fmt.Sprintf("%s, ", fis.Key.DebugString()))
+	}
+	if fis.Value != nil {
+		out.WriteString(fis.Value.DebugString())
+	}
+	if fis.Iterable != nil {
+		out.WriteString(fmt.Sprintf(" in %s", fis.Iterable.DebugString()))
+	}
+	out.WriteString(")")
+	if fis.Body != nil {
+		out.WriteString(fmt.Sprintf(" %s", fis.Body.DebugString()))
+	}
+	out.WriteString(fmt.Sprintf(" [%T]", fis))
+	return out.String()
+}
+
+// BREAK STATEMENT
+type BreakStatement struct {
+	Pos token.Position
+}
+
+func (bs *BreakStatement) statementNode()           {}
+func (bs *BreakStatement) Position() token.Position { return bs.Pos }
+func (bs *BreakStatement) Literal() string          { return "break" }
+func (bs *BreakStatement) String() string           { return "break;" }
+func (bs *BreakStatement) DebugString() string      { return fmt.Sprintf("break [%T];", bs) }
+
+// CONTINUE STATEMENT
+type ContinueStatement struct {
+	Pos token.Position
+}
+
+func (cs *ContinueStatement) statementNode()           {}
+func (cs *ContinueStatement) Position() token.Position { return cs.Pos }
+func (cs *ContinueStatement) Literal() string          { return "continue" }
+func (cs *ContinueStatement) String() string           { return "continue;" }
+func (cs *ContinueStatement) DebugString() string      { return fmt.Sprintf("continue [%T];", cs) }
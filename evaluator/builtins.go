@@ -0,0 +1,312 @@
+package evaluator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/menxqk/my-interpreter/object"
+)
+
+// RegisterBuiltin makes fn callable by name from evaluated code, a
+// builtin is shadowed by any variable or function of the same name
+func (e *Evaluator) RegisterBuiltin(name string, fn object.BuiltinFunction) {
+	e.builtins[name] = &object.Builtin{Name: name, Fn: fn}
+}
+
+func (e *Evaluator) registerCoreBuiltins() {
+	e.RegisterBuiltin("len", builtinLen)
+	e.RegisterBuiltin("print", e.builtinPrint)
+	e.RegisterBuiltin("println", e.builtinPrintln)
+	e.RegisterBuiltin("push", e.builtinPush)
+	e.RegisterBuiltin("pop", builtinPop)
+	e.RegisterBuiltin("keys", builtinKeys)
+	e.RegisterBuiltin("values", builtinValues)
+	e.RegisterBuiltin("has", builtinHas)
+	e.RegisterBuiltin("delete", builtinDelete)
+	e.RegisterBuiltin("type", builtinType)
+	e.RegisterBuiltin("str", builtinStr)
+	e.RegisterBuiltin("int", builtinInt)
+	e.RegisterBuiltin("float", builtinFloat)
+	e.RegisterBuiltin("char", builtinChar)
+}
+
+func checkArgCount(name string, args []object.Object, expected int) *object.Error {
+	if len(args) != expected {
+		return newError("wrong number of arguments to %s: %d, expected %d", name, len(args), expected)
+	}
+	return nil
+}
+
+func builtinLen(args ...object.Object) object.Object {
+	if errObj := checkArgCount("len", args, 1); errObj != nil {
+		return errObj
+	}
+
+	switch arg := args[0].(type) {
+	case *object.String:
+		return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+	case *object.Array:
+		return &object.Integer{Value: int64(len(arg.Elements))}
+	case *object.Dict:
+		return &object.Integer{Value: int64(len(arg.Elements))}
+	default:
+		return newError("len: argument must be %s, %s or %s, got %s", object.STR_OBJ, object.ARRAY_OBJ, object.DICT_OBJ, arg.Type())
+	}
+}
+
+func (e *Evaluator) builtinPrint(args ...object.Object) object.Object {
+	fmt.Fprint(e.out, joinInspect(args))
+	return NULL
+}
+
+func (e *Evaluator) builtinPrintln(args ...object.Object) object.Object {
+	fmt.Fprintln(e.out, joinInspect(args))
+	return NULL
+}
+
+func joinInspect(args []object.Object) string {
+	values := []string{}
+	for _, arg := range args {
+		values = append(values, arg.Inspect())
+	}
+	return strings.Join(values, " ")
+}
+
+// builtinPush appends an element to an array in place and returns the array
+func (e *Evaluator) builtinPush(args ...object.Object) object.Object {
+	if errObj := checkArgCount("push", args, 2); errObj != nil {
+		return errObj
+	}
+
+	arrObj, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("push: argument 1 must be %s, got %s", object.ARRAY_OBJ, args[0].Type())
+	}
+	arrType := arrObj.ArrType
+	if arrType == object.ANY_OBJ && len(arrObj.Elements) == 0 {
+		arrType = args[1].Type()
+	}
+	if args[1].Type() != arrType {
+		return newError("cannot assign %s to %s array", args[1].Type(), arrType)
+	}
+	if len(arrObj.Elements) > 0 {
+		if errObj := checkNestedArray(args[1], arrObj.Elements[0]); errObj != nil {
+			return errObj
+		}
+	}
+
+	if errObj := e.mem.CheckCollection(len(arrObj.Elements) + 1); errObj != nil {
+		return errObj
+	}
+	if errObj := e.mem.Grow(arrObj, 1); errObj != nil {
+		return errObj
+	}
+
+	arrObj.ArrType = arrType
+	arrObj.Elements = append(arrObj.Elements, args[1])
+	arrObj.Size = len(arrObj.Elements)
+
+	return arrObj
+}
+
+// builtinPop removes the last element of an array and returns it
+func builtinPop(args ...object.Object) object.Object {
+	if errObj := checkArgCount("pop", args, 1); errObj != nil {
+		return errObj
+	}
+
+	arrObj, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("pop: argument must be %s, got %s", object.ARRAY_OBJ, args[0].Type())
+	}
+	if len(arrObj.Elements) == 0 {
+		return newError("pop: empty array")
+	}
+
+	last := arrObj.Elements[len(arrObj.Elements)-1]
+	arrObj.Elements = arrObj.Elements[:len(arrObj.Elements)-1]
+	arrObj.Size = len(arrObj.Elements)
+
+	return last
+}
+
+func builtinKeys(args ...object.Object) object.Object {
+	if errObj := checkArgCount("keys", args, 1); errObj != nil {
+		return errObj
+	}
+
+	dictObj, ok := args[0].(*object.Dict)
+	if !ok {
+		return newError("keys: argument must be %s, got %s", object.DICT_OBJ, args[0].Type())
+	}
+
+	elements := []object.Object{}
+	for _, key := range dictObj.Keys() {
+		elements = append(elements, &object.String{Value: key})
+	}
+
+	return &object.Array{ArrType: object.STR_OBJ, Size: len(elements), Elements: elements}
+}
+
+// builtinValues returns the values of a dict in key order, they must all
+// be of the same type to fit in an array
+func builtinValues(args ...object.Object) object.Object {
+	if errObj := checkArgCount("values", args, 1); errObj != nil {
+		return errObj
+	}
+
+	dictObj, ok := args[0].(*object.Dict)
+	if !ok {
+		return newError("values: argument must be %s, got %s", object.DICT_OBJ, args[0].Type())
+	}
+
+	array := &object.Array{ArrType: object.ANY_OBJ, Elements: []object.Object{}}
+	for i, key := range dictObj.Keys() {
+		value := dictObj.Elements[key]
+		if i == 0 {
+			array.ArrType = value.Type()
+		}
+		if value.Type() != array.ArrType {
+			return newError("cannot mix %s and %s in array", array.ArrType, value.Type())
+		}
+		array.Elements = append(array.Elements, value)
+	}
+	array.Size = len(array.Elements)
+
+	return array
+}
+
+func builtinHas(args ...object.Object) object.Object {
+	if errObj := checkArgCount("has", args, 2); errObj != nil {
+		return errObj
+	}
+
+	dictObj, key, errObj := dictAndKey("has", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	_, ok := dictObj.Elements[key]
+	return &object.Boolean{Value: ok}
+}
+
+// builtinDelete removes a key from a dict and returns its value, or null
+// if the key was not present
+func builtinDelete(args ...object.Object) object.Object {
+	if errObj := checkArgCount("delete", args, 2); errObj != nil {
+		return errObj
+	}
+
+	dictObj, key, errObj := dictAndKey("delete", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	value, ok := dictObj.Elements[key]
+	if !ok {
+		return NULL
+	}
+	delete(dictObj.Elements, key)
+
+	return value
+}
+
+func dictAndKey(name string, args []object.Object) (*object.Dict, string, *object.Error) {
+	dictObj, ok := args[0].(*object.Dict)
+	if !ok {
+		return nil, "", newError("%s: argument 1 must be %s, got %s", name, object.DICT_OBJ, args[0].Type())
+	}
+
+	key, ok := args[1].(*object.String)
+	if !ok {
+		return nil, "", newError("dict key must be %s, got %s", object.STR_OBJ, args[1].Type())
+	}
+
+	return dictObj, key.Value, nil
+}
+
+func builtinType(args ...object.Object) object.Object {
+	if errObj := checkArgCount("type", args, 1); errObj != nil {
+		return errObj
+	}
+
+	return &object.String{Value: strings.ToLower(args[0].Type())}
+}
+
+func builtinStr(args ...object.Object) object.Object {
+	if errObj := checkArgCount("str", args, 1); errObj != nil {
+		return errObj
+	}
+
+	return &object.String{Value: args[0].Inspect()}
+}
+
+func builtinInt(args ...object.Object) object.Object {
+	if errObj := checkArgCount("int", args, 1); errObj != nil {
+		return errObj
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Integer:
+		return arg
+	case *object.Float:
+		return arg.ToType(object.IntType)
+	case *object.Char:
+		return &object.Integer{Value: int64(arg.Value)}
+	case *object.String:
+		value, err := strconv.ParseInt(strings.TrimSpace(arg.Value), 10, 64)
+		if err != nil {
+			return newError("int: cannot convert %q to %s", arg.Value, object.INT_OBJ)
+		}
+		return &object.Integer{Value: value}
+	default:
+		return newError("int: cannot convert %s to %s", arg.Type(), object.INT_OBJ)
+	}
+}
+
+func builtinFloat(args ...object.Object) object.Object {
+	if errObj := checkArgCount("float", args, 1); errObj != nil {
+		return errObj
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Float:
+		return arg
+	case *object.Integer:
+		return arg.ToType(object.FloatType)
+	case *object.String:
+		value, err := strconv.ParseFloat(strings.TrimSpace(arg.Value), 64)
+		if err != nil {
+			return newError("float: cannot convert %q to %s", arg.Value, object.FLOAT_OBJ)
+		}
+		return &object.Float{Value: value}
+	default:
+		return newError("float: cannot convert %s to %s", arg.Type(), object.FLOAT_OBJ)
+	}
+}
+
+func builtinChar(args ...object.Object) object.Object {
+	if errObj := checkArgCount("char", args, 1); errObj != nil {
+		return errObj
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Char:
+		return arg
+	case *object.Integer:
+		if arg.Value < 0 || arg.Value > utf8.MaxRune {
+			return newError("char: %d is not a valid character", arg.Value)
+		}
+		return &object.Char{Value: rune(arg.Value)}
+	case *object.String:
+		if utf8.RuneCountInString(arg.Value) != 1 {
+			return newError("char: cannot convert %q to %s", arg.Value, object.CHAR_OBJ)
+		}
+		r, _ := utf8.DecodeRuneInString(arg.Value)
+		return &object.Char{Value: r}
+	default:
+		return newError("char: cannot convert %s to %s", arg.Type(), object.CHAR_OBJ)
+	}
+}
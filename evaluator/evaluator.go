@@ -1,28 +1,122 @@
 package evaluator
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"reflect"
 
 	"github.com/menxqk/my-interpreter/ast"
 	"github.com/menxqk/my-interpreter/object"
 )
 
 var (
-	NULL  = &object.Null{}
-	TRUE  = &object.Boolean{Value: true}
-	FALSE = &object.Boolean{Value: false}
+	NULL     = &object.Null{}
+	TRUE     = &object.Boolean{Value: true}
+	FALSE    = &object.Boolean{Value: false}
+	BREAK    = &object.Break{}
+	CONTINUE = &object.Continue{}
+
+	// VOID is the result of calls to void functions, a null that
+	// cannot be used as a value
+	VOID = &object.Void{}
 )
 
 type Evaluator struct {
-	env *object.Environment
+	env      *object.Environment
+	builtins map[string]*object.Builtin
+	out      io.Writer
+
+	ctx          context.Context
+	maxSteps     int
+	steps        int
+	maxCallDepth int
+	callDepth    int
+	mem          *object.Memory
+	running      bool
 }
 
-func New() *Evaluator {
-	e := &Evaluator{env: object.NewEnvironment()}
+func New(opts ...Option) *Evaluator {
+	e := &Evaluator{
+		env:          object.NewEnvironment(),
+		builtins:     map[string]*object.Builtin{},
+		out:          os.Stdout,
+		maxCallDepth: DefaultMaxCallDepth,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.registerCoreBuiltins()
 	return e
 }
 
-func (e *Evaluator) Eval(node ast.Node) object.Object {
+// SetOutput sets the destination of print and println, os.Stdout by default
+func (e *Evaluator) SetOutput(w io.Writer) {
+	e.out = w
+}
+
+func (e *Evaluator) Set(name string, obj object.Object) object.Object {
+	return e.env.Set(name, obj)
+}
+
+func (e *Evaluator) Get(name string) (object.Object, bool) {
+	return e.env.Get(name)
+}
+
+// Call calls the function or builtin name with already evaluated arguments
+func (e *Evaluator) Call(name string, args ...object.Object) (result object.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = newError("internal error: %v", r)
+		}
+	}()
+	defer e.start()()
+
+	fnObj, ok := e.lookupFunction(name)
+	if !ok {
+		return newError("%q function not found", name)
+	}
+
+	return e.applyFunction(name, fnObj, args)
+}
+
+func (e *Evaluator) Eval(node ast.Node) (result object.Object) {
+	defer func() {
+		// an unexpected panic becomes an error instead of stopping the host program
+		if r := recover(); r != nil {
+			result = newError("internal error: %v", r)
+		}
+
+		// errors take the position of the innermost node that produced them
+		if err, ok := result.(*object.Error); ok && !err.Pos.IsValid() && !isNilNode(node) {
+			err.Pos = node.Position()
+		}
+	}()
+	defer e.start()()
+
+	if errObj := e.step(); errObj != nil {
+		return errObj
+	}
+
+	return e.eval(node)
+}
+
+// start resets the limits counters when evaluation starts from the
+// host, the returned function ends it
+func (e *Evaluator) start() func() {
+	if e.running {
+		return func() {}
+	}
+
+	e.running = true
+	e.steps = 0
+	e.callDepth = 0
+	e.mem.Reset()
+	return func() { e.running = false }
+}
+
+func (e *Evaluator) eval(node ast.Node) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return e.evalProgram(node)
@@ -42,6 +136,16 @@ func (e *Evaluator) Eval(node ast.Node) object.Object {
 		return e.evalAssignmentStatement(node)
 	case *ast.ReturnStatement:
 		return e.evalReturnStatement(node)
+	case *ast.WhileStatement:
+		return e.evalWhileStatement(node)
+	case *ast.ForStatement:
+		return e.evalForStatement(node)
+	case *ast.ForInStatement:
+		return e.evalForInStatement(node)
+	case *ast.BreakStatement:
+		return BREAK
+	case *ast.ContinueStatement:
+		return CONTINUE
 
 	// Expressions
 	case *ast.Identifier:
@@ -58,10 +162,10 @@ func (e *Evaluator) Eval(node ast.Node) object.Object {
 		return e.evalFunctionExpression(node)
 	case *ast.CallExpression:
 		return e.evalCallExpression(node)
-	case *ast.ArrayElementExpression:
-		return e.evalArrayElementExpression(node)
-	case *ast.DictElementExpression:
-		return e.evalDictElementExpression(node)
+	case *ast.IndexExpression:
+		return e.evalIndexExpression(node)
+	case *ast.SliceExpression:
+		return e.evalSliceExpression(node)
 
 	// Literals
 	case *ast.IntegerLiteral:
@@ -71,7 +175,7 @@ func (e *Evaluator) Eval(node ast.Node) object.Object {
 	case *ast.CharLiteral:
 		return &object.Char{Value: node.Value}
 	case *ast.StringLiteral:
-		return &object.String{Value: node.Value}
+		return e.alloc(&object.String{Value: node.Value})
 	case *ast.BooleanLiteral:
 		if node.Value == true {
 			return TRUE
@@ -105,6 +209,10 @@ func (e *Evaluator) evalProgram(program *ast.Program) object.Object {
 	return result
 }
 
+func isNilNode(node ast.Node) bool {
+	return node == nil || reflect.ValueOf(node).IsNil()
+}
+
 func isError(obj object.Object) bool {
 	if obj != nil {
 		return obj.Type() == object.ERROR_OBJ
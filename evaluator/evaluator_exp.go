@@ -3,18 +3,22 @@ package evaluator
 import (
 	"github.com/menxqk/my-interpreter/ast"
 	"github.com/menxqk/my-interpreter/object"
+	"github.com/menxqk/my-interpreter/token"
 )
 
 func (e *Evaluator) evalIdentifier(ident *ast.Identifier) object.Object {
 	obj, ok := e.env.Get(ident.Name)
 	if !ok {
+		if builtin, ok := e.builtins[ident.Name]; ok {
+			return builtin
+		}
 		return &object.Null{}
 	}
 	return obj
 }
 
 func (e *Evaluator) evalPrefixExpression(exp *ast.PrefixExpression) object.Object {
-	right := e.Eval(exp.Expression)
+	right := e.evalValue(exp.Expression)
 	if isError(right) {
 		return right
 	}
@@ -26,18 +30,21 @@ func (e *Evaluator) evalPrefixExpression(exp *ast.PrefixExpression) object.Objec
 		return e.evalBangOperatorExpression(right)
 	case "-":
 		return e.evalMinusOperatorExpression(right)
+	case "~":
+		return e.evalTildeOperatorExpression(right)
 	default:
 		return newError("unknown operator: %s%s", operator, right.Type())
 	}
 }
 
 func (e *Evaluator) evalBangOperatorExpression(right object.Object) object.Object {
-	switch right {
-	case TRUE:
-		return FALSE
-	case FALSE:
+	switch right := right.(type) {
+	case *object.Boolean:
+		if right.Value {
+			return FALSE
+		}
 		return TRUE
-	case NULL:
+	case *object.Null:
 		return TRUE
 	default:
 		return FALSE
@@ -57,22 +64,43 @@ func (e *Evaluator) evalMinusOperatorExpression(right object.Object) object.Obje
 	}
 }
 
+func (e *Evaluator) evalTildeOperatorExpression(right object.Object) object.Object {
+	switch right.Type() {
+	case object.INT_OBJ:
+		value := right.(*object.Integer).Value
+		return &object.Integer{Value: ^value}
+	default:
+		return newError("unknown operator: ~%s", right.Type())
+	}
+}
+
 func (e *Evaluator) evalGroupedExpression(exp *ast.GroupedExpression) object.Object {
-	return e.Eval(exp.Expression)
+	return e.evalValue(exp.Expression)
 }
 
 func (e *Evaluator) evalInfixExpression(exp *ast.InfixExpression) object.Object {
-	left := e.Eval(exp.Left)
+	if exp.Operator == "&&" || exp.Operator == "||" {
+		return e.evalLogicalExpression(exp)
+	}
+
+	left := e.evalValue(exp.Left)
 	if isError(left) {
 		return left
 	}
-	right := e.Eval(exp.Right)
+	right := e.evalValue(exp.Right)
 	if isError(right) {
 		return right
 	}
+
+	return e.evalInfixOperator(exp.Operator, left, right)
+}
+
+// evalInfixOperator applies a binary operator to two evaluated operands,
+// the memory limits are enforced on the object it creates
+func (e *Evaluator) evalInfixOperator(operator string, left, right object.Object) object.Object {
 	typeForObjects := getTypeForObjects(left, right)
 	if typeForObjects == object.NullType {
-		return newError("illegal operation %s %s %s", left.Type(), exp.Operator, right.Type())
+		return newError("illegal operation %s %s %s", left.Type(), operator, right.Type())
 	}
 
 	leftVal := left.ToType(typeForObjects)
@@ -80,7 +108,6 @@ func (e *Evaluator) evalInfixExpression(exp *ast.InfixExpression) object.Object
 
 	var result object.Object
 
-	operator := exp.Operator
 	switch operator {
 	case "+":
 		result = leftVal.Add(rightVal)
@@ -90,6 +117,20 @@ func (e *Evaluator) evalInfixExpression(exp *ast.InfixExpression) object.Object
 		result = leftVal.Mul(rightVal)
 	case "/":
 		result = leftVal.Div(rightVal)
+	case "%":
+		result = leftVal.Mod(rightVal)
+	case "**":
+		result = leftVal.Pow(rightVal)
+	case "&":
+		result = leftVal.BitAnd(rightVal)
+	case "|":
+		result = leftVal.BitOr(rightVal)
+	case "^":
+		result = leftVal.BitXor(rightVal)
+	case "<<":
+		result = leftVal.Shl(rightVal)
+	case ">>":
+		result = leftVal.Shr(rightVal)
 	case "==":
 		result = leftVal.Equ(rightVal)
 	case "!=":
@@ -106,24 +147,37 @@ func (e *Evaluator) evalInfixExpression(exp *ast.InfixExpression) object.Object
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 
-	if result == nil {
+	// operations a type does not support result in null
+	if result == nil || result.Type() == object.NULL_OBJ {
 		return newError("illegal operation %s %s %s", left.Type(), operator, right.Type())
 	}
 
-	return result
+	return e.alloc(result)
+}
+
+// evalLogicalExpression evaluates the right operand only when the left
+// one does not decide the result
+func (e *Evaluator) evalLogicalExpression(exp *ast.InfixExpression) object.Object {
+	left := e.evalCondition(exp.Left)
+	if isError(left) {
+		return left
+	}
+
+	leftVal := left.(*object.Boolean).Value
+	if exp.Operator == "&&" && !leftVal || exp.Operator == "||" && leftVal {
+		return left
+	}
+
+	return e.evalCondition(exp.Right)
 }
 
 func (e *Evaluator) evalIfExpression(exp *ast.IfExpression) object.Object {
-	cond := e.Eval(exp.Condition)
+	cond := e.evalCondition(exp.Condition)
 	if isError(cond) {
 		return cond
 	}
 
-	b, ok := cond.(*object.Boolean)
-	if !ok {
-		return newError("expected %T for boolean, got %T", &object.Boolean{}, cond)
-	}
-
+	b := cond.(*object.Boolean)
 	if b.Value == true && exp.Consequence != nil {
 		return e.Eval(exp.Consequence)
 	} else if b.Value == false && exp.Alternative != nil {
@@ -133,118 +187,311 @@ func (e *Evaluator) evalIfExpression(exp *ast.IfExpression) object.Object {
 	}
 }
 
-func (e *Evaluator) evalCallExpression(exp *ast.CallExpression) object.Object {
-	fnObj, ok := e.env.Get(exp.Identifier.Name)
-	if !ok {
-		return newError("%q function not found", exp.Identifier.Name)
+// evalValue evaluates exp, whose value is used, making sure it is not
+// the result of a void function
+func (e *Evaluator) evalValue(exp ast.Expression) object.Object {
+	obj := e.Eval(exp)
+	if _, ok := obj.(*object.Void); ok {
+		errObj := newError("%s (no value) used as value", exp.String())
+		errObj.Pos = exp.Position()
+		return errObj
 	}
+	return obj
+}
 
-	fn, ok := fnObj.(*object.Function)
-	if !ok {
-		return newError("%q is not a function, got %s", exp.Identifier.Name, fnObj.Type())
+// evalCondition evaluates exp and makes sure it is a boolean
+func (e *Evaluator) evalCondition(exp ast.Expression) object.Object {
+	cond := e.evalValue(exp)
+	if isError(cond) {
+		return cond
+	}
+
+	if _, ok := cond.(*object.Boolean); !ok {
+		return newError("expected %T for boolean, got %T", &object.Boolean{}, cond)
 	}
 
-	if len(exp.Arguments) != len(fn.Parameters) {
-		return newError("wrong number of arguments: %d, expected %d", len(exp.Arguments), len(fn.Parameters))
+	return cond
+}
+
+func (e *Evaluator) evalCallExpression(exp *ast.CallExpression) object.Object {
+	name := exp.Function.String()
+
+	var fnObj object.Object
+	if ident, ok := exp.Function.(*ast.Identifier); ok {
+		fnObj, ok = e.lookupFunction(ident.Name)
+		if !ok {
+			return newError("%q function not found", ident.Name)
+		}
+	} else {
+		// any other expression evaluating to a function can be called
+		fnObj = e.evalValue(exp.Function)
+		if isError(fnObj) {
+			return fnObj
+		}
 	}
 
-	params := map[string]object.Object{}
-	for i, arg := range exp.Arguments {
-		argObj := e.Eval(arg)
+	args := []object.Object{}
+	for _, arg := range exp.Arguments {
+		argObj := e.evalValue(arg)
 		if isError(argObj) {
 			return argObj
 		}
+		args = append(args, argObj)
+	}
+
+	return e.applyFunction(name, fnObj, args)
+}
+
+// lookupFunction finds what name refers to, variables and user
+// functions shadow builtins
+func (e *Evaluator) lookupFunction(name string) (object.Object, bool) {
+	if obj, ok := e.env.Get(name); ok {
+		return obj, true
+	}
+
+	builtin, ok := e.builtins[name]
+	if !ok {
+		return nil, false
+	}
+	return builtin, true
+}
+
+func (e *Evaluator) applyFunction(name string, fnObj object.Object, args []object.Object) object.Object {
+	switch fn := fnObj.(type) {
+	case *object.Builtin:
+		result := fn.Fn(args...)
+		if result == nil {
+			return NULL
+		}
+		// builtins returning one of their arguments created nothing
+		for _, arg := range args {
+			if result == arg {
+				return result
+			}
+		}
+		return e.alloc(result)
+	case *object.Function:
+		return e.callFunction(name, fn, args)
+	default:
+		return newError("%q is not a function, got %s", name, fnObj.Type())
+	}
+}
+
+func (e *Evaluator) callFunction(name string, fn *object.Function, args []object.Object) object.Object {
+	if errObj := e.enterCall(); errObj != nil {
+		return errObj
+	}
+	defer e.leaveCall()
 
+	// the zero value of a func variable
+	if fn.Body == nil {
+		return newError("%q is a null function", name)
+	}
+
+	if len(args) != len(fn.Parameters) {
+		return newError("wrong number of arguments: %d, expected %d", len(args), len(fn.Parameters))
+	}
+
+	// every call runs in its own environment enclosed by the one
+	// where the function was declared
+	callEnv := object.NewEnclosedEnvironment(fn.Env)
+	for i, argObj := range args {
 		param := fn.Parameters[i]
-		if argObj.Type() == param.Type {
-			// e.env.Set(param.Name, argObj)
-			params[param.Name] = argObj
-			continue
-		} else {
+		if argObj.Type() != param.Type {
 			return newError("wrong type for argument %d, got=%s; expected:%s", i+1, argObj.Type(), param.Type)
 		}
+		callEnv.Set(param.Name, argObj)
 	}
 
-	for param, obj := range params {
-		e.env.Set(param, obj)
+	result := e.evalInEnvironment(fn.Body, callEnv)
+	if isError(result) {
+		return result
 	}
 
-	result := e.Eval(fn.Body)
+	// void functions yield no value
+	if fn.Identifier.Type == token.VOID_TYPE {
+		return VOID
+	}
 
-	for param := range params {
-		e.env.Del(param)
+	// a body that ends without a return statement returns null
+	var resValue object.Object = NULL
+	if retVal, ok := result.(*object.ReturnValue); ok {
+		resValue = retVal.Value
 	}
 
-	if result.Type() == object.RET_VAL_OBJ {
-		resValue := result.(*object.ReturnValue).Value
-		if resValue.Type() != fn.Identifier.Type {
-			return newError("function %q returned %s, expected %s", exp.Identifier.Name, resValue.Type(), fn.Identifier.Type)
+	if resValue.Type() != fn.Identifier.Type {
+		return newError("function %q returned %s, expected %s", name, resValue.Type(), fn.Identifier.Type)
+	}
+
+	return resValue
+}
+
+func (e *Evaluator) evalIndexExpression(exp *ast.IndexExpression) object.Object {
+	obj := e.evalIndexTarget(exp.Left)
+	if isError(obj) {
+		return obj
+	}
+
+	index := e.evalValue(exp.Index)
+	if isError(index) {
+		return index
+	}
+
+	switch obj := obj.(type) {
+	case *object.Array:
+		return e.evalArrayIndexExpression(obj, index)
+	case *object.Dict:
+		return e.evalDictIndexExpression(obj, index)
+	case *object.String:
+		return e.evalStringIndexExpression(obj, index)
+	default:
+		return newError("%q not an array, dict or string, got %s", exp.Left.String(), obj.Type())
+	}
+}
+
+// evalIndexTarget evaluates the object being indexed or sliced
+func (e *Evaluator) evalIndexTarget(left ast.Expression) object.Object {
+	if ident, ok := left.(*ast.Identifier); ok {
+		obj, found := e.env.Get(ident.Name)
+		if !found {
+			return newError("%q not found", ident.Name)
 		}
+		return obj
 	}
 
-	return result
+	return e.evalValue(left)
 }
 
-func (e *Evaluator) evalArrayElementExpression(arrElem *ast.ArrayElementExpression) object.Object {
-	name := arrElem.Identifier.Name
+func (e *Evaluator) evalArrayIndexExpression(arrObj *object.Array, index object.Object) object.Object {
+	i, errObj := arrayIndex(arrObj, index)
+	if errObj != nil {
+		return errObj
+	}
 
-	obj, ok := e.env.Get(name)
+	return arrObj.Elements[i]
+}
+
+func (e *Evaluator) evalDictIndexExpression(dictObj *object.Dict, index object.Object) object.Object {
+	key, ok := index.(*object.String)
 	if !ok {
-		return newError("array %q not found", name)
+		return newError("dict key must be %s, got %s", object.STR_OBJ, index.Type())
 	}
 
-	if obj.Type() != object.ARRAY_OBJ {
-		return newError("%q not an array", name)
+	elem, ok := dictObj.Elements[key.Value]
+	if !ok {
+		return &object.Null{}
 	}
 
-	arrObj, ok := obj.(*object.Array)
+	return elem
+}
+
+func (e *Evaluator) evalStringIndexExpression(strObj *object.String, index object.Object) object.Object {
+	intObj, ok := index.(*object.Integer)
 	if !ok {
-		return newError("could not convert %q to array", name)
+		return newError("string index must be %s, got %s", object.INT_OBJ, index.Type())
 	}
 
-	if arrElem.Index > arrObj.Size-1 {
-		return newError("index (%d) out of bounds (%d)", arrElem.Index, arrObj.Size-1)
+	runes := []rune(strObj.Value)
+	i := intObj.Value
+	size := int64(len(runes))
+	if i < 0 {
+		i += size
 	}
 
-	if arrElem.Expression != nil {
-		newObj := e.Eval(arrElem.Expression)
-		if newObj.Type() != arrObj.ArrType {
-			return newError("cannot assign %s to %s array", newObj.Type(), arrObj.ArrType)
-		}
-		arrObj.Elements[arrElem.Index] = newObj
+	if i < 0 || i >= size {
+		return newError("index (%d) out of bounds (%d)", intObj.Value, size-1)
 	}
 
-	return arrObj.Elements[arrElem.Index]
+	return &object.Char{Value: runes[i]}
 }
 
-func (e *Evaluator) evalDictElementExpression(dictElem *ast.DictElementExpression) object.Object {
-	name := dictElem.Identifier.Name
+func (e *Evaluator) evalSliceExpression(exp *ast.SliceExpression) object.Object {
+	obj := e.evalIndexTarget(exp.Left)
+	if isError(obj) {
+		return obj
+	}
 
-	obj, ok := e.env.Get(name)
-	if !ok {
-		return newError("dict %q not found", name)
+	var size int64
+	switch obj := obj.(type) {
+	case *object.Array:
+		size = int64(len(obj.Elements))
+	case *object.String:
+		size = int64(len([]rune(obj.Value)))
+	default:
+		return newError("%q not an array or string, got %s", exp.Left.String(), obj.Type())
+	}
+
+	start, errObj := e.evalSliceBound(exp.Start, 0, size)
+	if errObj != nil {
+		return errObj
+	}
+	end, errObj := e.evalSliceBound(exp.End, size, size)
+	if errObj != nil {
+		return errObj
+	}
+	if start > end {
+		return newError("slice bounds out of range [%d:%d]", start, end)
+	}
+
+	switch obj := obj.(type) {
+	case *object.Array:
+		elements := make([]object.Object, end-start)
+		copy(elements, obj.Elements[start:end])
+		return e.alloc(&object.Array{ArrType: obj.ArrType, Size: len(elements), Elements: elements})
+	default:
+		runes := []rune(obj.(*object.String).Value)
+		return e.alloc(&object.String{Value: string(runes[start:end])})
+	}
+}
+
+// evalSliceBound evaluates a slice bound against a sequence of length
+// size, negative bounds count from the end and an omitted bound is def
+func (e *Evaluator) evalSliceBound(exp ast.Expression, def int64, size int64) (int64, *object.Error) {
+	if exp == nil {
+		return def, nil
 	}
 
-	if obj.Type() != object.DICT_OBJ {
-		return newError("%q not a dict", name)
+	obj := e.evalValue(exp)
+	if errObj, ok := obj.(*object.Error); ok {
+		return 0, errObj
 	}
 
-	dictObj, ok := obj.(*object.Dict)
+	intObj, ok := obj.(*object.Integer)
 	if !ok {
-		return newError("could not convert %q to dict", name)
+		return 0, newError("slice index must be %s, got %s", object.INT_OBJ, obj.Type())
+	}
+
+	i := intObj.Value
+	if i < 0 {
+		i += size
 	}
 
-	if dictElem.Expression != nil {
-		newObj := e.Eval(dictElem.Expression)
-		dictObj.Elements[dictElem.Key] = newObj
+	if i < 0 || i > size {
+		return 0, newError("slice index (%d) out of bounds (%d)", intObj.Value, size)
 	}
 
-	_, ok = dictObj.Elements[dictElem.Key]
+	return i, nil
+}
+
+// arrayIndex checks index against the bounds of arrObj, negative indexes
+// count from the end of the array
+func arrayIndex(arrObj *object.Array, index object.Object) (int, *object.Error) {
+	intObj, ok := index.(*object.Integer)
 	if !ok {
-		return &object.Null{}
+		return 0, newError("array index must be %s, got %s", object.INT_OBJ, index.Type())
 	}
 
-	return dictObj.Elements[dictElem.Key]
+	i := intObj.Value
+	size := int64(len(arrObj.Elements))
+	if i < 0 {
+		i += size
+	}
+
+	if i < 0 || i >= size {
+		return 0, newError("index (%d) out of bounds (%d)", intObj.Value, size-1)
+	}
+
+	return int(i), nil
 }
 
 func (e *Evaluator) evalFunctionExpression(fnExp *ast.FunctionExpression) object.Object {
@@ -256,6 +503,14 @@ func (e *Evaluator) evalFunctionExpression(fnExp *ast.FunctionExpression) object
 	}
 }
 
+func (e *Evaluator) evalInEnvironment(node ast.Node, env *object.Environment) object.Object {
+	outer := e.env
+	e.env = env
+	defer func() { e.env = outer }()
+
+	return e.Eval(node)
+}
+
 func getTypeForObjects(left, right object.Object) object.ObjectType {
 
 	if left.Type() == object.INT_OBJ && right.Type() == object.INT_OBJ {
@@ -271,7 +526,8 @@ func getTypeForObjects(left, right object.Object) object.ObjectType {
 	}
 
 	if left.Type() == object.ARRAY_OBJ && right.Type() == object.ARRAY_OBJ {
-		if left.(*object.Array).ArrType == right.(*object.Array).ArrType {
+		leftType, rightType := left.(*object.Array).ArrType, right.(*object.Array).ArrType
+		if leftType == rightType || leftType == object.ANY_OBJ || rightType == object.ANY_OBJ {
 			return object.ArrayType
 		}
 		return object.NullType
@@ -10,7 +10,10 @@ func (e *Evaluator) evalArrayLiteral(lit *ast.ArrayLiteral) object.Object {
 	array.Elements = []object.Object{}
 
 	for _, elem := range lit.Elements {
-		obj := e.Eval(elem)
+		obj := e.evalValue(elem)
+		if isError(obj) {
+			return obj
+		}
 		if array.ArrType == "" {
 			array.ArrType = obj.Type()
 		}
@@ -19,9 +22,12 @@ func (e *Evaluator) evalArrayLiteral(lit *ast.ArrayLiteral) object.Object {
 		}
 		array.Elements = append(array.Elements, obj)
 	}
+	if array.ArrType == "" {
+		array.ArrType = object.ANY_OBJ
+	}
 	array.Size = len(array.Elements)
 
-	return array
+	return e.alloc(array)
 }
 
 func (e *Evaluator) evalDictLiteral(lit *ast.DictLiteral) object.Object {
@@ -29,9 +35,12 @@ func (e *Evaluator) evalDictLiteral(lit *ast.DictLiteral) object.Object {
 	dict.Elements = map[string]object.Object{}
 
 	for k, v := range lit.Elements {
-		obj := e.Eval(v)
+		obj := e.evalValue(v)
+		if isError(obj) {
+			return obj
+		}
 		dict.Elements[k] = obj
 	}
 
-	return dict
+	return e.alloc(dict)
 }
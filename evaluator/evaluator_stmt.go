@@ -3,6 +3,7 @@ package evaluator
 import (
 	"github.com/menxqk/my-interpreter/ast"
 	"github.com/menxqk/my-interpreter/object"
+	"github.com/menxqk/my-interpreter/token"
 )
 
 func (e *Evaluator) evalExpressionStatement(stmt *ast.ExpressionStatement) object.Object {
@@ -10,14 +11,16 @@ func (e *Evaluator) evalExpressionStatement(stmt *ast.ExpressionStatement) objec
 }
 
 func (e *Evaluator) evalBlockStatement(block *ast.BlockStatement) object.Object {
-	var result object.Object
+	var result object.Object = NULL
 
 	for _, stmt := range block.Statements {
 		result = e.Eval(stmt)
 
+		// return values, break and continue are passed up until they
+		// reach the function call, the program or the loop
 		switch result := result.(type) {
-		case *object.ReturnValue:
-			return result.Value
+		case *object.ReturnValue, *object.Break, *object.Continue:
+			return result
 		case *object.Error:
 			return result
 		}
@@ -45,7 +48,10 @@ func (e *Evaluator) evalArrayDeclarationStatement(stmt *ast.ArrayDeclarationStat
 
 	name := stmt.Identifier.Name
 
-	obj := e.Eval(stmt.Expression)
+	obj := e.evalValue(stmt.Expression)
+	if isError(obj) {
+		return obj
+	}
 	// if expression is null, set zero value Object for the type
 	if obj.Type() == object.NULL_OBJ {
 		obj = object.GetZeroValueObject(object.ARRAY_OBJ)
@@ -58,6 +64,15 @@ func (e *Evaluator) evalArrayDeclarationStatement(stmt *ast.ArrayDeclarationStat
 	arrObj := obj.(*object.Array)
 	arrObj.ArrType = stmt.Identifier.Type
 	arrObj.Size = stmt.Size
+	if stmt.Dimensions > 1 {
+		arrObj.ArrType = object.ARRAY_OBJ
+		for _, elem := range arrObj.Elements {
+			errObj := setNestedArrayType(elem, stmt.Identifier.Type, stmt.Dimensions-1)
+			if errObj != nil {
+				return errObj
+			}
+		}
+	}
 
 	if len(arrObj.Elements) > arrObj.Size && arrObj.Size > 0 {
 		return newError("%d elements exceed array capacity %d", len(arrObj.Elements), arrObj.Size)
@@ -67,6 +82,13 @@ func (e *Evaluator) evalArrayDeclarationStatement(stmt *ast.ArrayDeclarationStat
 		arrObj.Size = len(arrObj.Elements)
 	}
 
+	if errObj := e.mem.CheckCollection(arrObj.Size); errObj != nil {
+		return errObj
+	}
+	if errObj := e.mem.Grow(arrObj, arrObj.Size-len(arrObj.Elements)); errObj != nil {
+		return errObj
+	}
+
 	allElements := make([]object.Object, arrObj.Size, arrObj.Size)
 	for i := range allElements {
 		allElements[i] = &object.Null{}
@@ -85,13 +107,67 @@ func (e *Evaluator) evalArrayDeclarationStatement(stmt *ast.ArrayDeclarationStat
 	return result
 }
 
+// checkNestedArray checks that newObj keeps the element type of old when
+// both are inner arrays of a multi-dimensional array, an empty newObj
+// taking it
+func checkNestedArray(newObj, old object.Object) *object.Error {
+	newArr, ok := newObj.(*object.Array)
+	if !ok {
+		return nil
+	}
+	oldArr, ok := old.(*object.Array)
+	if !ok {
+		return nil
+	}
+
+	if newArr.ArrType == object.ANY_OBJ && len(newArr.Elements) == 0 {
+		newArr.ArrType = oldArr.ArrType
+	}
+	if newArr.ArrType != oldArr.ArrType {
+		return newError("cannot assign %s array to %s array", newArr.ArrType, oldArr.ArrType)
+	}
+	return nil
+}
+
+// setNestedArrayType sets the element type of an inner array of a
+// multi-dimensional array, dims being the dimensions left below obj
+func setNestedArrayType(obj object.Object, elemType string, dims int) *object.Error {
+	arrObj, ok := obj.(*object.Array)
+	if !ok {
+		return newError("cannot assign %s to %s array", obj.Type(), object.ARRAY_OBJ)
+	}
+
+	arrObj.ArrType = elemType
+	if dims > 1 {
+		arrObj.ArrType = object.ARRAY_OBJ
+	}
+	arrObj.Size = len(arrObj.Elements)
+
+	for _, elem := range arrObj.Elements {
+		if dims > 1 {
+			if errObj := setNestedArrayType(elem, elemType, dims-1); errObj != nil {
+				return errObj
+			}
+			continue
+		}
+		if elem.Type() != elemType {
+			return newError("cannot assign %s to %s array", elem.Type(), elemType)
+		}
+	}
+
+	return nil
+}
+
 func (e *Evaluator) evalVariableDeclarationStatement(stmt *ast.VariableDeclarationStatement) object.Object {
 	var result object.Object
 
 	name := stmt.Identifier.Name
 	varType := stmt.Identifier.Type
 
-	obj := e.Eval(stmt.Expression)
+	obj := e.evalValue(stmt.Expression)
+	if isError(obj) {
+		return obj
+	}
 	// if expression is null, set zero value Object for the type
 	if obj.Type() == object.NULL_OBJ {
 		obj = object.GetZeroValueObject(varType)
@@ -107,14 +183,28 @@ func (e *Evaluator) evalVariableDeclarationStatement(stmt *ast.VariableDeclarati
 }
 
 func (e *Evaluator) evalAssignmentStatement(stmt *ast.AssignmentStatement) object.Object {
+	switch left := stmt.Left.(type) {
+	case *ast.Identifier:
+		return e.evalIdentifierAssignment(left, stmt)
+	case *ast.IndexExpression:
+		return e.evalIndexAssignment(left, stmt)
+	default:
+		return newError("cannot assign to %s", stmt.Left.String())
+	}
+}
+
+func (e *Evaluator) evalIdentifierAssignment(ident *ast.Identifier, stmt *ast.AssignmentStatement) object.Object {
 	var result object.Object
 
-	obj, ok := e.env.Get(stmt.Identifier.Name)
+	obj, ok := e.env.Get(ident.Name)
 	if !ok {
-		return newError("%q not declared", stmt.Identifier.Name)
+		return newError("%q not declared", ident.Name)
 	}
 
-	expObj := e.Eval(stmt.Expression)
+	expObj := e.evalAssignedValue(stmt, obj)
+	if isError(expObj) {
+		return expObj
+	}
 
 	if expObj.Type() != obj.Type() {
 		return newError("cannot assign %s to %s", expObj.Type(), obj.Type())
@@ -135,11 +225,248 @@ func (e *Evaluator) evalAssignmentStatement(stmt *ast.AssignmentStatement) objec
 		expObjArray.ArrType = arrObj.ArrType
 	}
 
-	result = e.env.Set(stmt.Identifier.Name, expObj)
+	result, _ = e.env.Assign(ident.Name, expObj)
 
 	return result
 }
 
+func (e *Evaluator) evalIndexAssignment(exp *ast.IndexExpression, stmt *ast.AssignmentStatement) object.Object {
+	obj := e.evalIndexTarget(exp.Left)
+	if isError(obj) {
+		return obj
+	}
+
+	index := e.evalValue(exp.Index)
+	if isError(index) {
+		return index
+	}
+
+	switch obj := obj.(type) {
+	case *object.Array:
+		i, errObj := arrayIndex(obj, index)
+		if errObj != nil {
+			return errObj
+		}
+
+		newObj := e.evalAssignedValue(stmt, obj.Elements[i])
+		if isError(newObj) {
+			return newObj
+		}
+		if newObj.Type() != obj.ArrType {
+			return newError("cannot assign %s to %s array", newObj.Type(), obj.ArrType)
+		}
+		if errObj := checkNestedArray(newObj, obj.Elements[i]); errObj != nil {
+			return errObj
+		}
+
+		obj.Elements[i] = newObj
+		return newObj
+	case *object.Dict:
+		key, ok := index.(*object.String)
+		if !ok {
+			return newError("dict key must be %s, got %s", object.STR_OBJ, index.Type())
+		}
+
+		current, ok := obj.Elements[key.Value]
+		if !ok {
+			current = &object.Null{}
+		}
+
+		newObj := e.evalAssignedValue(stmt, current)
+		if isError(newObj) {
+			return newObj
+		}
+
+		obj.Elements[key.Value] = newObj
+		if !ok {
+			if errObj := e.mem.Grow(obj, 1); errObj != nil {
+				delete(obj.Elements, key.Value)
+				return errObj
+			}
+		}
+		return newObj
+	case *object.String:
+		return newError("cannot assign to %s index", object.STR_OBJ)
+	default:
+		return newError("%q not an array, dict or string, got %s", exp.Left.String(), obj.Type())
+	}
+}
+
+// evalAssignedValue evaluates the value stmt assigns to a target whose
+// value is current
+func (e *Evaluator) evalAssignedValue(stmt *ast.AssignmentStatement, current object.Object) object.Object {
+	var right object.Object
+	switch stmt.Operator {
+	case "++", "--":
+		right = &object.Integer{Value: 1}
+	default:
+		right = e.evalValue(stmt.Expression)
+		if isError(right) {
+			return right
+		}
+	}
+
+	operator, ok := token.CompoundOperators[stmt.Operator]
+	if !ok {
+		return right
+	}
+
+	return e.evalInfixOperator(operator, current, right)
+}
+
 func (e *Evaluator) evalReturnStatement(stmt *ast.ReturnStatement) object.Object {
-	return e.Eval(stmt.ReturnValue)
+	if stmt.ReturnValue == nil {
+		return &object.ReturnValue{Value: NULL}
+	}
+
+	obj := e.evalValue(stmt.ReturnValue)
+	if isError(obj) {
+		return obj
+	}
+
+	return &object.ReturnValue{Value: obj}
+}
+
+func (e *Evaluator) evalWhileStatement(stmt *ast.WhileStatement) object.Object {
+	for {
+		cond := e.evalCondition(stmt.Condition)
+		if isError(cond) {
+			return cond
+		}
+		if !cond.(*object.Boolean).Value {
+			break
+		}
+
+		if result, stop := e.evalLoopBody(stmt.Body); stop {
+			return result
+		}
+	}
+
+	return NULL
+}
+
+func (e *Evaluator) evalForStatement(stmt *ast.ForStatement) object.Object {
+	// variables declared in the init statement are scoped to the loop
+	outer := e.env
+	e.env = object.NewEnclosedEnvironment(outer)
+	defer func() { e.env = outer }()
+
+	if stmt.Init != nil {
+		init := e.Eval(stmt.Init)
+		if isError(init) {
+			return init
+		}
+	}
+
+	for {
+		if stmt.Condition != nil {
+			cond := e.evalCondition(stmt.Condition)
+			if isError(cond) {
+				return cond
+			}
+			if !cond.(*object.Boolean).Value {
+				break
+			}
+		}
+
+		if result, stop := e.evalLoopBody(stmt.Body); stop {
+			return result
+		}
+
+		if stmt.Post != nil {
+			post := e.Eval(stmt.Post)
+			if isError(post) {
+				return post
+			}
+		}
+	}
+
+	return NULL
+}
+
+func (e *Evaluator) evalForInStatement(stmt *ast.ForInStatement) object.Object {
+	iterable := e.evalValue(stmt.Iterable)
+	if isError(iterable) {
+		return iterable
+	}
+
+	// keys and values are taken before the loop starts, so changes
+	// made by the body do not affect the iteration
+	keys := []object.Object{}
+	values := []object.Object{}
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for i, elem := range iterable.Elements {
+			keys = append(keys, &object.Integer{Value: int64(i)})
+			values = append(values, elem)
+		}
+	case *object.String:
+		for i, r := range []rune(iterable.Value) {
+			keys = append(keys, &object.Integer{Value: int64(i)})
+			values = append(values, &object.Char{Value: r})
+		}
+	case *object.Dict:
+		for _, k := range iterable.Keys() {
+			keys = append(keys, &object.String{Value: k})
+			values = append(values, iterable.Elements[k])
+		}
+	default:
+		return newError("cannot iterate over %s", iterable.Type())
+	}
+
+	// a dict loop with a single variable walks the keys
+	if _, isDict := iterable.(*object.Dict); isDict && stmt.Key == nil {
+		values = keys
+	}
+
+	outer := e.env
+	defer func() { e.env = outer }()
+
+	for i := range values {
+		// loop variables are declared in a new environment on every iteration
+		e.env = object.NewEnclosedEnvironment(outer)
+
+		if stmt.Key != nil {
+			if obj := e.declareLoopVariable(stmt.Key, keys[i]); isError(obj) {
+				return obj
+			}
+		}
+		if obj := e.declareLoopVariable(stmt.Value, values[i]); isError(obj) {
+			return obj
+		}
+
+		if result, stop := e.evalLoopBody(stmt.Body); stop {
+			return result
+		}
+	}
+
+	return NULL
+}
+
+func (e *Evaluator) declareLoopVariable(ident *ast.Identifier, obj object.Object) object.Object {
+	// if value is null, set zero value Object for the type
+	if obj.Type() == object.NULL_OBJ {
+		obj = object.GetZeroValueObject(ident.Type)
+	}
+
+	if obj.Type() != ident.Type {
+		return newError("cannot assign %s to %s", obj.Type(), ident.Type)
+	}
+
+	return e.env.Set(ident.Name, obj)
+}
+
+// evalLoopBody runs one iteration of a loop body in its own environment and
+// reports whether the loop has to stop, along with the loop result
+func (e *Evaluator) evalLoopBody(body *ast.BlockStatement) (object.Object, bool) {
+	result := e.evalInEnvironment(body, object.NewEnclosedEnvironment(e.env))
+
+	switch result.(type) {
+	case *object.ReturnValue, *object.Error:
+		return result, true
+	case *object.Break:
+		return NULL, true
+	default:
+		return NULL, false
+	}
 }
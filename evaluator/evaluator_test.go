@@ -1,7 +1,11 @@
 package evaluator
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/menxqk/my-interpreter/lexer"
 	"github.com/menxqk/my-interpreter/object"
@@ -27,7 +31,7 @@ func TestEval(t *testing.T) {
 
 		{"-1;", "-1", object.INT_OBJ},
 		{"-3.55;", "-3.550000", object.FLOAT_OBJ},
-		{"-s;", "ERROR: unknown operator: -NULL", object.ERROR_OBJ},
+		{"-s;", "ERROR: 1:1: unknown operator: -NULL", object.ERROR_OBJ},
 
 		{"int x = 5;", "5", object.INT_OBJ},
 		{"float y = 55.55;", "55.550000", object.FLOAT_OBJ},
@@ -105,16 +109,16 @@ func TestEval(t *testing.T) {
 		{"z <= z;", "true", object.BOOL_OBJ},
 		{"z <= w;", "true", object.BOOL_OBJ},
 
-		{"a = x + y;", "ERROR: \"a\" not declared", object.ERROR_OBJ},
+		{"a = x + y;", "ERROR: 1:1: \"a\" not declared", object.ERROR_OBJ},
 
 		{"int a = x + x;", "10", object.INT_OBJ},
-		{"int a = x + y;", "ERROR: cannot assign FLOAT to INT", object.ERROR_OBJ},
+		{"int a = x + y;", "ERROR: 1:1: cannot assign FLOAT to INT", object.ERROR_OBJ},
 
-		{"float a = x + x;", "ERROR: cannot assign INT to FLOAT", object.ERROR_OBJ},
+		{"float a = x + x;", "ERROR: 1:1: cannot assign INT to FLOAT", object.ERROR_OBJ},
 		{"float a = x + y;", "60.550000", object.FLOAT_OBJ},
 		{"a = x + y;", "60.550000", object.FLOAT_OBJ},
 
-		{"char b = w + z;", "ERROR: cannot assign STRING to CHAR", object.ERROR_OBJ},
+		{"char b = w + z;", "ERROR: 1:1: cannot assign STRING to CHAR", object.ERROR_OBJ},
 		{"string b = w + w;", "cc", object.STR_OBJ},
 		{"string b = w + z;", "cA string", object.STR_OBJ},
 		{"string b = z + w;", "A stringc", object.STR_OBJ},
@@ -123,7 +127,7 @@ func TestEval(t *testing.T) {
 
 		{"int add(int a, int b) { return a + b; }", "int add(int a, int b) { return (a + b); }", object.FN_OBJ},
 		{"add(1, 2);", "3", object.INT_OBJ},
-		{"add(x, y);", "ERROR: wrong type for argument 2, got=FLOAT; expected:INT", object.ERROR_OBJ},
+		{"add(x, y);", "ERROR: 1:1: wrong type for argument 2, got=FLOAT; expected:INT", object.ERROR_OBJ},
 
 		{"if (x > y) { return x; } else { return y; }", "55.550000", object.FLOAT_OBJ},
 		{"if (x < y) { return x; } else { return y; }", "5", object.INT_OBJ},
@@ -139,6 +143,17 @@ func TestEval(t *testing.T) {
 		{"dict e = {\"four\": 4, \"five\": 5, \"six\": 6}; e[\"six\"];", "6", object.INT_OBJ},
 		{"dict f = d + e; f[\"three\"];", "3", object.INT_OBJ},
 		{"f[\"five\"];", "5", object.INT_OBJ},
+
+		{"bool done;", "false", object.BOOL_OBJ},
+		{"bool big = x > 3;", "true", object.BOOL_OBJ},
+		{"done = !big;", "false", object.BOOL_OBJ},
+		{"bool wrong = 1;", "ERROR: 1:1: cannot assign INT to BOOLEAN", object.ERROR_OBJ},
+		{"big = 0;", "ERROR: 1:1: cannot assign INT to BOOLEAN", object.ERROR_OBJ},
+		{"bool flags[3] = [true]; flags[1] = big; flags;", "boolean[3] [true, true, null]", object.ARRAY_OBJ},
+		{"flags[2] = 'c';", "ERROR: 1:1: cannot assign CHAR to BOOLEAN array", object.ERROR_OBJ},
+		{"bool even(int n) { return n % 2 == 0; } even(4);", "true", object.BOOL_OBJ},
+		{"bool both(bool a, bool b) { return a && b; } both(even(2), flags[0]);", "true", object.BOOL_OBJ},
+		{"both(1, true);", "ERROR: 1:1: wrong type for argument 1, got=INT; expected:BOOLEAN", object.ERROR_OBJ},
 	}
 
 	e := New()
@@ -161,3 +176,624 @@ func TestEval(t *testing.T) {
 		}
 	}
 }
+
+func TestEvalErrorPosition(t *testing.T) {
+	input := "int x = 1;\nstring s = \"a\";\nint y = x;\n  y = x + true;"
+
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	result := New().Eval(program)
+
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("expected %T, got %T (%s)", errObj, result, result.Inspect())
+	}
+
+	if errObj.Pos.Line != 4 || errObj.Pos.Column != 9 {
+		t.Fatalf("expected error at 4:9, got %s", errObj.Pos)
+	}
+}
+
+type evalTest struct {
+	Line       string
+	Result     string
+	ResultType string
+}
+
+// runEvalTests evaluates every line in order with the same Evaluator
+func runEvalTests(t *testing.T, tests []evalTest) {
+	t.Helper()
+
+	e := New()
+	for _, tt := range tests {
+		l := lexer.New(tt.Line)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if p.HasErrors() {
+			t.Fatalf("parse errors for %q: %v", tt.Line, p.Errors())
+		}
+		result := e.Eval(program)
+
+		if result == nil {
+			t.Fatalf("got nil result for %q", tt.Line)
+		}
+
+		if result.Inspect() != tt.Result {
+			t.Fatalf("expected %q as result, got %q for %q", tt.Result, result.Inspect(), tt.Line)
+		}
+
+		if result.Type() != tt.ResultType {
+			t.Fatalf("expected %s as result type, got %s for %q", tt.ResultType, result.Type(), tt.Line)
+		}
+	}
+}
+
+func TestEvalFunctionScope(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"int a = 100;", "100", object.INT_OBJ},
+		{"int add(int a, int b) { int c = a + b; return c; }", "int add(int a, int b) { int c = (a + b); return c; }", object.FN_OBJ},
+		{"add(1, 2);", "3", object.INT_OBJ},
+		{"a;", "100", object.INT_OBJ},
+		{"c;", "null", object.NULL_OBJ},
+
+		{"int counter = 0;", "0", object.INT_OBJ},
+		{"int incr(int n) { counter = counter + n; return counter; }", "int incr(int n) { counter = (counter + n); return counter; }", object.FN_OBJ},
+		{"incr(2); incr(3);", "5", object.INT_OBJ},
+		{"counter;", "5", object.INT_OBJ},
+
+		{"int fact(int n) { if (n < 2) { return 1; } else { return n * fact(n - 1); } }", "int fact(int n) { if (n < 2) { return 1; } else { return (n * fact((n - 1))); }; }", object.FN_OBJ},
+		{"fact(5);", "120", object.INT_OBJ},
+
+		{"int outer(int x) { int inner(int y) { return x + y; } return inner(10); }", "int outer(int x) { int inner(int y) { return (x + y); } return inner(10); }", object.FN_OBJ},
+		{"outer(5);", "15", object.INT_OBJ},
+		{"inner(1);", "ERROR: 1:1: \"inner\" function not found", object.ERROR_OBJ},
+		{"x;", "null", object.NULL_OBJ},
+	})
+}
+
+func TestEvalReturn(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"int sign(int n) { if (n < 0) { return -1; } if (n == 0) { return 0; } return 1; }", "int sign(int n) { if (n < 0) { return -1; } ; if (n == 0) { return 0; } ; return 1; }", object.FN_OBJ},
+		{"sign(-5);", "-1", object.INT_OBJ},
+		{"sign(0);", "0", object.INT_OBJ},
+		{"sign(7);", "1", object.INT_OBJ},
+
+		{"int fact(int n) { if (n < 2) { return 1; } return n * fact(n - 1); }", "int fact(int n) { if (n < 2) { return 1; } ; return (n * fact((n - 1))); }", object.FN_OBJ},
+		{"fact(6);", "720", object.INT_OBJ},
+		{"fact(3) + 1;", "7", object.INT_OBJ},
+
+		{"int wrong() { return 1.5; }", "int wrong() { return 1.500000; }", object.FN_OBJ},
+		{"wrong();", "ERROR: 1:1: function \"wrong\" returned FLOAT, expected INT", object.ERROR_OBJ},
+		{"int missing() { int x = 1; }", "int missing() { int x = 1; }", object.FN_OBJ},
+		{"missing();", "ERROR: 1:1: function \"missing\" returned NULL, expected INT", object.ERROR_OBJ},
+
+		{"int early() { return; }", "int early() { return; }", object.FN_OBJ},
+		{"early();", "ERROR: 1:1: function \"early\" returned NULL, expected INT", object.ERROR_OBJ},
+
+		{"int total = 0;", "0", object.INT_OBJ},
+		{"void add(int n) { if (n < 0) { return; } total += n; }", "void add(int n) { if (n < 0) { return; } ; total += n; }", object.FN_OBJ},
+		{"add(5);", "null", object.NULL_OBJ},
+		{"add(-1); add(2); total;", "7", object.INT_OBJ},
+		{"void last() { total; }", "void last() { total; }", object.FN_OBJ},
+		{"last();", "null", object.NULL_OBJ},
+		{"int z = last();", "ERROR: 1:9: last() (no value) used as value", object.ERROR_OBJ},
+		{"1 + last();", "ERROR: 1:5: last() (no value) used as value", object.ERROR_OBJ},
+		{"total = last();", "ERROR: 1:9: last() (no value) used as value", object.ERROR_OBJ},
+		{"add(last());", "ERROR: 1:5: last() (no value) used as value", object.ERROR_OBJ},
+		{"[last()];", "ERROR: 1:2: last() (no value) used as value", object.ERROR_OBJ},
+		{"int a[] = [1]; a[last()] = 2;", "ERROR: 1:18: last() (no value) used as value", object.ERROR_OBJ},
+		{"int wrap() { return last(); } wrap();", "ERROR: 1:21: last() (no value) used as value", object.ERROR_OBJ},
+		{"add(\"s\");", "ERROR: 1:1: wrong type for argument 1, got=STRING; expected:INT", object.ERROR_OBJ},
+
+		{"return 5; 10;", "5", object.INT_OBJ},
+	})
+}
+
+func TestEvalFunctionValues(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"func mul = int (int a, int b) { return a * b; };", "int (int a, int b) { return (a * b); }", object.FN_OBJ},
+		{"mul(3, 4);", "12", object.INT_OBJ},
+		{"int apply(func f, int a, int b) { return f(a, b); }", "int apply(func f, int a, int b) { return f(a, b); }", object.FN_OBJ},
+		{"apply(mul, 5, 6);", "30", object.INT_OBJ},
+		{"apply(int (int a, int b) { return a - b; }, 5, 6);", "-1", object.INT_OBJ},
+		{"int (int x) { return x * x; }(9);", "81", object.INT_OBJ},
+
+		{"func adder(int n) { return int (int x) { return x + n; }; }", "func adder(int n) { return int (int x) { return (x + n); }; }", object.FN_OBJ},
+		{"func add2 = adder(2); add2(5);", "7", object.INT_OBJ},
+		{"adder(10)(5);", "15", object.INT_OBJ},
+
+		{"func ops[] = [mul, adder(1)];", "function[2] [int (int a, int b) { return (a * b); }, int (int x) { return (x + n); }]", object.ARRAY_OBJ},
+		{"ops[1](ops[0](2, 3));", "7", object.INT_OBJ},
+		{"int count = 0; void each(int n, func visit) { for (int i = 0; i < n; i++) { visit(i); } }", "void each(int n, func visit) { for (int i = 0; (i < n); i++) { visit(i); } }", object.FN_OBJ},
+		{"each(4, void (int i) { count += i; }); count;", "6", object.INT_OBJ},
+
+		{"func none; none;", "null", object.FN_OBJ},
+		{"none(1);", "ERROR: 1:1: \"none\" is a null function", object.ERROR_OBJ},
+		{"none = mul; none(2, 2);", "4", object.INT_OBJ},
+		{"func bad = 1;", "ERROR: 1:1: cannot assign INT to FUNCTION", object.ERROR_OBJ},
+		{"count(1);", "ERROR: 1:1: \"count\" is not a function, got INT", object.ERROR_OBJ},
+		{"[1, 2][0](1);", "ERROR: 1:1: \"[1, 2][0]\" is not a function, got INT", object.ERROR_OBJ},
+		{"int (int x) { return 1.5; }(1);", "ERROR: 1:1: function \"int (int x) { return 1.500000; }\" returned FLOAT, expected INT", object.ERROR_OBJ},
+	})
+}
+
+func TestEvalLoops(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"int i = 0; int sum = 0;", "0", object.INT_OBJ},
+		{"while (i < 5) { i = i + 1; sum = sum + i; }", "null", object.NULL_OBJ},
+		{"sum;", "15", object.INT_OBJ},
+
+		{"sum = 0; for (int j = 0; j < 10; j = j + 1) { if (j == 5) { break; } sum = sum + j; }", "null", object.NULL_OBJ},
+		{"sum;", "10", object.INT_OBJ},
+		{"j;", "null", object.NULL_OBJ},
+
+		{"sum = 0; for (int j = 0; j < 10; j = j + 1) { if (j < 8) { continue; } sum = sum + j; }", "null", object.NULL_OBJ},
+		{"sum;", "17", object.INT_OBJ},
+
+		{"i = 0; for (; i < 3;) { i = i + 1; }", "null", object.NULL_OBJ},
+		{"i;", "3", object.INT_OBJ},
+
+		{"i = 0; for (;;) { i = i + 1; if (i == 4) { break; } }", "null", object.NULL_OBJ},
+		{"i;", "4", object.INT_OBJ},
+
+		{"while (true) { int local = 1; break; }", "null", object.NULL_OBJ},
+		{"local;", "null", object.NULL_OBJ},
+
+		{"int find(int n) { int k = 0; while (true) { if (k * k >= n) { return k; } k = k + 1; } return -1; }", "int find(int n) { int k = 0; while (true) { if ((k * k) >= n) { return k; } ; k = (k + 1); } return -1; }", object.FN_OBJ},
+		{"find(17);", "5", object.INT_OBJ},
+
+		{"int total = 0; for (int a = 0; a < 3; a = a + 1) { for (int b = 0; b < 3; b = b + 1) { if (b == a) { continue; } total = total + 1; } }", "null", object.NULL_OBJ},
+		{"total;", "6", object.INT_OBJ},
+
+		{"while (1) { }", "ERROR: 1:1: expected *object.Boolean for boolean, got *object.Integer", object.ERROR_OBJ},
+	})
+}
+
+func TestEvalForIn(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"int arr[] = [1, 2, 3, 4];", "int[4] [1, 2, 3, 4]", object.ARRAY_OBJ},
+		{"int sum = 0; for (int x in arr) { sum = sum + x; }", "null", object.NULL_OBJ},
+		{"sum;", "10", object.INT_OBJ},
+		{"x;", "null", object.NULL_OBJ},
+
+		{"sum = 0; for (int i, int x in arr) { sum = sum + i * x; }", "null", object.NULL_OBJ},
+		{"sum;", "20", object.INT_OBJ},
+
+		{"string out = \"\"; for (char c in \"héllo\") { out = c + out; }", "null", object.NULL_OBJ},
+		{"out;", "olléh", object.STR_OBJ},
+
+		{"dict d = {\"b\": 2, \"c\": 3, \"a\": 1};", "dict{\"a\": 1, \"b\": 2, \"c\": 3}", object.DICT_OBJ},
+		{"string ks = \"\"; for (string k in d) { ks = ks + k; }", "null", object.NULL_OBJ},
+		{"ks;", "abc", object.STR_OBJ},
+		{"ks = \"\"; sum = 0; for (string k, int v in d) { ks = ks + k; sum = sum * 10 + v; }", "null", object.NULL_OBJ},
+		{"ks;", "abc", object.STR_OBJ},
+		{"sum;", "123", object.INT_OBJ},
+
+		{"int empty[3]; int n = 0; for (int z in empty) { n = n + 1 + z; }", "null", object.NULL_OBJ},
+		{"n;", "3", object.INT_OBJ},
+
+		{"for (float f in arr) { }", "ERROR: 1:1: cannot assign INT to FLOAT", object.ERROR_OBJ},
+		{"for (int v in 5) { }", "ERROR: 1:1: cannot iterate over INT", object.ERROR_OBJ},
+		{"sum = 0; for (int x in arr) { if (x == 3) { break; } sum = sum + x; }", "null", object.NULL_OBJ},
+		{"sum;", "3", object.INT_OBJ},
+	})
+}
+
+func TestEvalLogicalOperators(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"true && true;", "true", object.BOOL_OBJ},
+		{"true && false;", "false", object.BOOL_OBJ},
+		{"false || true;", "true", object.BOOL_OBJ},
+		{"false || false;", "false", object.BOOL_OBJ},
+		{"1 < 2 && 3 > 2;", "true", object.BOOL_OBJ},
+		{"1 > 2 || 3 == 3 && 4 != 4;", "false", object.BOOL_OBJ},
+		{"(1 > 2 || 3 == 3) && 4 == 4;", "true", object.BOOL_OBJ},
+		{"!(1 > 2) && !false;", "true", object.BOOL_OBJ},
+
+		{"int n = 0;", "0", object.INT_OBJ},
+		{"n != 0 && 10 / n > 1;", "false", object.BOOL_OBJ},
+		{"n == 0 || 10 / n > 1;", "true", object.BOOL_OBJ},
+
+		{"1 && true;", "ERROR: 1:3: expected *object.Boolean for boolean, got *object.Integer", object.ERROR_OBJ},
+		{"true || 1;", "true", object.BOOL_OBJ},
+		{"false || 1;", "ERROR: 1:7: expected *object.Boolean for boolean, got *object.Integer", object.ERROR_OBJ},
+	})
+}
+
+func TestEvalArithmeticAndBitwiseOperators(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"17 % 5;", "2", object.INT_OBJ},
+		{"-17 % 5;", "-2", object.INT_OBJ},
+		{"7.5 % 2;", "1.500000", object.FLOAT_OBJ},
+		{"2 ** 10;", "1024", object.INT_OBJ},
+		{"2 ** 3 ** 2;", "512", object.INT_OBJ},
+		{"3 ** 0;", "1", object.INT_OBJ},
+		{"2.0 ** 0.5;", "1.414214", object.FLOAT_OBJ},
+		{"2 * 3 ** 2;", "18", object.INT_OBJ},
+		{"2 ** -1;", "ERROR: 1:3: negative exponent: 2 ** -1", object.ERROR_OBJ},
+
+		{"12 & 10;", "8", object.INT_OBJ},
+		{"12 | 10;", "14", object.INT_OBJ},
+		{"12 ^ 10;", "6", object.INT_OBJ},
+		{"1 << 4;", "16", object.INT_OBJ},
+		{"256 >> 3;", "32", object.INT_OBJ},
+		{"-16 >> 2;", "-4", object.INT_OBJ},
+		{"~5;", "-6", object.INT_OBJ},
+		{"~0 & 255;", "255", object.INT_OBJ},
+		{"1 << -1;", "ERROR: 1:3: negative shift count: 1 << -1", object.ERROR_OBJ},
+		{"1.5 & 1;", "ERROR: 1:5: illegal operation FLOAT & INT", object.ERROR_OBJ},
+		{"~1.5;", "ERROR: 1:1: unknown operator: ~FLOAT", object.ERROR_OBJ},
+
+		{"1 + 2 << 1;", "6", object.INT_OBJ},
+		{"1 << 2 < 5;", "true", object.BOOL_OBJ},
+		{"6 & 3 == 2;", "ERROR: 1:3: illegal operation INT & BOOLEAN", object.ERROR_OBJ},
+		{"(6 & 3) == 2;", "true", object.BOOL_OBJ},
+		{"1 | 6 ^ 3 & 5;", "7", object.INT_OBJ},
+		{"int h = 31 * 7 + 3; h % 8;", "4", object.INT_OBJ},
+
+		{"\"a\" - \"b\";", "ERROR: 1:5: illegal operation STRING - STRING", object.ERROR_OBJ},
+	})
+}
+
+func TestEvalArithmeticErrors(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"1 / 0;", "ERROR: 1:3: division by zero: 1 / 0", object.ERROR_OBJ},
+		{"int n = 0; 10 % n;", "ERROR: 1:15: division by zero: 10 % 0", object.ERROR_OBJ},
+		{"int min = -9223372036854775807 - 1;", "-9223372036854775808", object.INT_OBJ},
+		{"min / -1;", "ERROR: 1:5: integer overflow: -9223372036854775808 / -1", object.ERROR_OBJ},
+		{"min % -1;", "0", object.INT_OBJ},
+		{"1.0 / 0;", "+Inf", object.FLOAT_OBJ},
+		{"int f(int a) { return 100 / a; }", "int f(int a) { return (100 / a); }", object.FN_OBJ},
+		{"f(0);", "ERROR: 1:27: division by zero: 100 / 0", object.ERROR_OBJ},
+		{"f(4);", "25", object.INT_OBJ},
+		{"int e = -2; 3 ** e;", "ERROR: 1:15: negative exponent: 3 ** -2", object.ERROR_OBJ},
+		{"8 >> -3;", "ERROR: 1:3: negative shift count: 8 >> -3", object.ERROR_OBJ},
+	})
+}
+
+func TestEvalRecoversFromPanic(t *testing.T) {
+	l := lexer.New("true == \"a\";")
+	p := parser.New(l)
+	program := p.ParseProgram()
+	result := New().Eval(program)
+
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("expected %T, got %T (%s)", errObj, result, result.Inspect())
+	}
+
+	if !strings.HasPrefix(errObj.Inspect(), "ERROR: 1:6: internal error: ") {
+		t.Fatalf("unexpected error %q", errObj.Inspect())
+	}
+}
+
+func TestEvalIndexExpressions(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"int arr[] = [10, 20, 30, 40];", "int[4] [10, 20, 30, 40]", object.ARRAY_OBJ},
+		{"int i = 1;", "1", object.INT_OBJ},
+		{"arr[i];", "20", object.INT_OBJ},
+		{"arr[i + 1];", "30", object.INT_OBJ},
+		{"arr[i * 2 + 1] = arr[i] + arr[0];", "30", object.INT_OBJ},
+		{"arr;", "int[4] [10, 20, 30, 30]", object.ARRAY_OBJ},
+		{"arr[-1];", "30", object.INT_OBJ},
+		{"arr[-4];", "10", object.INT_OBJ},
+		{"arr[4];", "ERROR: 1:1: index (4) out of bounds (3)", object.ERROR_OBJ},
+		{"arr[-5];", "ERROR: 1:1: index (-5) out of bounds (3)", object.ERROR_OBJ},
+		{"arr[\"one\"];", "ERROR: 1:1: array index must be INT, got STRING", object.ERROR_OBJ},
+		{"arr[1.5];", "ERROR: 1:1: array index must be INT, got FLOAT", object.ERROR_OBJ},
+		{"arr[0] = 1.5;", "ERROR: 1:1: cannot assign FLOAT to INT array", object.ERROR_OBJ},
+	})
+
+	runEvalTests(t, []evalTest{
+		{"dict d = {\"one\": 1, \"two\": 2};", "dict{\"one\": 1, \"two\": 2}", object.DICT_OBJ},
+		{"string key = \"two\";", "two", object.STR_OBJ},
+		{"d[key];", "2", object.INT_OBJ},
+		{"d[\"t\" + \"wo\"];", "2", object.INT_OBJ},
+		{"d[key + \"s\"] = 22;", "22", object.INT_OBJ},
+		{"d;", "dict{\"one\": 1, \"two\": 2, \"twos\": 22}", object.DICT_OBJ},
+		{"d[\"none\"];", "null", object.NULL_OBJ},
+		{"d[1];", "ERROR: 1:1: dict key must be STRING, got INT", object.ERROR_OBJ},
+		{"int x = 1; x[0];", "ERROR: 1:12: \"x\" not an array, dict or string, got INT", object.ERROR_OBJ},
+		{"y[0];", "ERROR: 1:1: \"y\" not found", object.ERROR_OBJ},
+
+		{"int squares[5]; for (int i = 0; i < 5; i = i + 1) { squares[i] = i * i; }", "null", object.NULL_OBJ},
+		{"squares;", "int[5] [0, 1, 4, 9, 16]", object.ARRAY_OBJ},
+	})
+
+	runEvalTests(t, []evalTest{
+		{"[1, 2, 3][1];", "2", object.INT_OBJ},
+		{"\"abc\"[0];", "a", object.CHAR_OBJ},
+		{"\"héllo\"[-4];", "é", object.CHAR_OBJ},
+		{"\"abc\"[3];", "ERROR: 1:1: index (3) out of bounds (2)", object.ERROR_OBJ},
+		{"string s = \"abc\"; s[0] = 'x';", "ERROR: 1:19: cannot assign to STRING index", object.ERROR_OBJ},
+		{"dict m = {\"rows\": [1, 2, 3], \"meta\": {\"n\": 3}};", "dict{\"meta\": dict{\"n\": 3}, \"rows\": int[3] [1, 2, 3]}", object.DICT_OBJ},
+		{"m[\"rows\"][2];", "3", object.INT_OBJ},
+		{"m[\"rows\"][2] = 30;", "30", object.INT_OBJ},
+		{"m[\"meta\"][\"n\"] = 4;", "4", object.INT_OBJ},
+		{"m[\"meta\"][\"n\"] + m[\"rows\"][-1];", "34", object.INT_OBJ},
+		{"m[\"rows\"][5];", "ERROR: 1:1: index (5) out of bounds (2)", object.ERROR_OBJ},
+		{"dict f() { return {\"a\": \"xyz\"}; }", "dict f() { return {a: \"xyz\"}; }", object.FN_OBJ},
+		{"f()[\"a\"][1];", "y", object.CHAR_OBJ},
+		{"int grid[2][] = [[1, 2], [3, 4]];", "array[2] [int[2] [1, 2], int[2] [3, 4]]", object.ARRAY_OBJ},
+		{"grid[1][0];", "3", object.INT_OBJ},
+		{"grid[1][0] = 5;", "5", object.INT_OBJ},
+		{"grid[0] = [7, 8];", "int[2] [7, 8]", object.ARRAY_OBJ},
+		{"grid;", "array[2] [int[2] [7, 8], int[2] [5, 4]]", object.ARRAY_OBJ},
+		{"grid[0] = [\"a\"];", "ERROR: 1:1: cannot assign STRING array to INT array", object.ERROR_OBJ},
+		{"grid[1][0] = 1.5;", "ERROR: 1:1: cannot assign FLOAT to INT array", object.ERROR_OBJ},
+		{"grid[0] = 1;", "ERROR: 1:1: cannot assign INT to ARRAY array", object.ERROR_OBJ},
+		{"int bad[][] = [1, 2];", "ERROR: 1:1: cannot assign INT to ARRAY array", object.ERROR_OBJ},
+	})
+}
+
+func TestEvalAssignments(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"int x = 10;", "10", object.INT_OBJ},
+		{"x += 5;", "15", object.INT_OBJ},
+		{"x -= 3;", "12", object.INT_OBJ},
+		{"x *= 2;", "24", object.INT_OBJ},
+		{"x /= 5;", "4", object.INT_OBJ},
+		{"x %= 3;", "1", object.INT_OBJ},
+		{"x++;", "2", object.INT_OBJ},
+		{"x--; x--;", "0", object.INT_OBJ},
+		{"x += 1.5;", "ERROR: 1:1: cannot assign FLOAT to INT", object.ERROR_OBJ},
+		{"x /= 0;", "ERROR: 1:1: division by zero: 0 / 0", object.ERROR_OBJ},
+		{"y += 1;", "ERROR: 1:1: \"y\" not declared", object.ERROR_OBJ},
+		{"float f = 1.5; f++;", "2.500000", object.FLOAT_OBJ},
+		{"string s = \"ab\"; s += \"c\";", "abc", object.STR_OBJ},
+		{"s -= \"c\";", "ERROR: 1:1: illegal operation STRING - STRING", object.ERROR_OBJ},
+
+		{"int a[] = [1, 2, 3];", "int[3] [1, 2, 3]", object.ARRAY_OBJ},
+		{"a[0] += 10;", "11", object.INT_OBJ},
+		{"a[-1]++;", "4", object.INT_OBJ},
+		{"a[1] *= a[0];", "22", object.INT_OBJ},
+		{"a;", "int[3] [11, 22, 4]", object.ARRAY_OBJ},
+		{"a[1] = \"b\";", "ERROR: 1:1: cannot assign STRING to INT array", object.ERROR_OBJ},
+		{"a[3] += 1;", "ERROR: 1:1: index (3) out of bounds (2)", object.ERROR_OBJ},
+
+		{"dict d = {\"n\": 1, \"rows\": [1, 2]};", "dict{\"n\": 1, \"rows\": int[2] [1, 2]}", object.DICT_OBJ},
+		{"d[\"n\"] += 41;", "42", object.INT_OBJ},
+		{"d[\"rows\"][1] *= 10;", "20", object.INT_OBJ},
+		{"d[\"new\"] = 'c';", "c", object.CHAR_OBJ},
+		{"d;", "dict{\"n\": 42, \"new\": c, \"rows\": int[2] [1, 20]}", object.DICT_OBJ},
+		{"d[\"none\"]++;", "ERROR: 1:1: illegal operation NULL + INT", object.ERROR_OBJ},
+
+		{"int sum = 0; for (int i = 0; i < 5; i++) { sum += i; }", "null", object.NULL_OBJ},
+		{"sum;", "10", object.INT_OBJ},
+	})
+}
+
+func TestEvalSliceExpressions(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"int a[] = [1, 2, 3, 4, 5];", "int[5] [1, 2, 3, 4, 5]", object.ARRAY_OBJ},
+		{"a[1:3];", "int[2] [2, 3]", object.ARRAY_OBJ},
+		{"a[:2];", "int[2] [1, 2]", object.ARRAY_OBJ},
+		{"a[3:];", "int[2] [4, 5]", object.ARRAY_OBJ},
+		{"a[:];", "int[5] [1, 2, 3, 4, 5]", object.ARRAY_OBJ},
+		{"a[-2:];", "int[2] [4, 5]", object.ARRAY_OBJ},
+		{"a[1:-1];", "int[3] [2, 3, 4]", object.ARRAY_OBJ},
+		{"a[2:2];", "int[0] []", object.ARRAY_OBJ},
+		{"int b[] = a[1:3]; b[0] = 20;", "20", object.INT_OBJ},
+		{"a;", "int[5] [1, 2, 3, 4, 5]", object.ARRAY_OBJ},
+		{"a[3:1];", "ERROR: 1:1: slice bounds out of range [3:1]", object.ERROR_OBJ},
+		{"a[0:6];", "ERROR: 1:1: slice index (6) out of bounds (5)", object.ERROR_OBJ},
+		{"a[-6:];", "ERROR: 1:1: slice index (-6) out of bounds (5)", object.ERROR_OBJ},
+		{"a[\"x\":];", "ERROR: 1:1: slice index must be INT, got STRING", object.ERROR_OBJ},
+
+		{"string s = \"héllo\";", "héllo", object.STR_OBJ},
+		{"s[1:3];", "él", object.STR_OBJ},
+		{"s[-3:];", "llo", object.STR_OBJ},
+		{"s[:1] + s[2:];", "hllo", object.STR_OBJ},
+		{"\"abc\"[1:][0];", "b", object.CHAR_OBJ},
+		{"dict d = {\"a\": 1}; d[0:1];", "ERROR: 1:20: \"d\" not an array or string, got DICT", object.ERROR_OBJ},
+	})
+}
+
+func TestEvalBuiltins(t *testing.T) {
+	runEvalTests(t, []evalTest{
+		{"len(\"héllo\");", "5", object.INT_OBJ},
+		{"len([1, 2, 3]);", "3", object.INT_OBJ},
+		{"len({\"a\": 1});", "1", object.INT_OBJ},
+		{"len(1);", "ERROR: 1:1: len: argument must be STRING, ARRAY or DICT, got INT", object.ERROR_OBJ},
+		{"len();", "ERROR: 1:1: wrong number of arguments to len: 0, expected 1", object.ERROR_OBJ},
+
+		{"int a[] = [1, 2];", "int[2] [1, 2]", object.ARRAY_OBJ},
+		{"push(a, 3);", "int[3] [1, 2, 3]", object.ARRAY_OBJ},
+		{"push(a, \"x\");", "ERROR: 1:1: cannot assign STRING to INT array", object.ERROR_OBJ},
+		{"pop(a);", "3", object.INT_OBJ},
+		{"a;", "int[2] [1, 2]", object.ARRAY_OBJ},
+		// the sum of arrays does not share its elements with the operands
+		{"int b[]; b = a + [5]; push(a, 7); b;", "int[3] [1, 2, 5]", object.ARRAY_OBJ},
+		{"pop(a); a + [8] == a + [9];", "false", object.BOOL_OBJ},
+		{"pop([]);", "ERROR: 1:1: pop: empty array", object.ERROR_OBJ},
+		// empty arrays without a declared element type take the type of
+		// their first element
+		{"[];", "any[0] []", object.ARRAY_OBJ},
+		{"push([], 'c');", "char[1] [c]", object.ARRAY_OBJ},
+		{"[] + [1.5];", "float[1] [1.500000]", object.ARRAY_OBJ},
+		{"int m[][] = [[1], [2]];", "array[2] [int[1] [1], int[1] [2]]", object.ARRAY_OBJ},
+		{"push(m, [\"x\"]);", "ERROR: 1:1: cannot assign STRING array to INT array", object.ERROR_OBJ},
+		{"push(m, []); push(m[2], 3); m;", "array[3] [int[1] [1], int[1] [2], int[1] [3]]", object.ARRAY_OBJ},
+
+		{"dict d = {\"b\": 2, \"a\": 1};", "dict{\"a\": 1, \"b\": 2}", object.DICT_OBJ},
+		{"keys(d);", "string[2] [a, b]", object.ARRAY_OBJ},
+		{"values(d);", "int[2] [1, 2]", object.ARRAY_OBJ},
+		{"has(d, \"a\");", "true", object.BOOL_OBJ},
+		{"delete(d, \"a\");", "1", object.INT_OBJ},
+		{"has(d, \"a\");", "false", object.BOOL_OBJ},
+		{"delete(d, \"a\");", "null", object.NULL_OBJ},
+		{"has(d, 1);", "ERROR: 1:1: dict key must be STRING, got INT", object.ERROR_OBJ},
+		{"values({\"a\": 1, \"b\": \"x\"});", "ERROR: 1:1: cannot mix INT and STRING in array", object.ERROR_OBJ},
+		{"dict empty = {}; keys(empty);", "string[0] []", object.ARRAY_OBJ},
+		{"values(empty);", "any[0] []", object.ARRAY_OBJ},
+		{"push(values(empty), 4);", "int[1] [4]", object.ARRAY_OBJ},
+
+		{"type(1.5);", "float", object.STR_OBJ},
+		{"type(len);", "builtin", object.STR_OBJ},
+		{"str(42) + \"!\";", "42!", object.STR_OBJ},
+		{"int(3.9);", "3", object.INT_OBJ},
+		{"int(\" 12 \");", "12", object.INT_OBJ},
+		{"int('a');", "97", object.INT_OBJ},
+		{"int(\"x\");", "ERROR: 1:1: int: cannot convert \"x\" to INT", object.ERROR_OBJ},
+		{"float(2) / 4;", "0.500000", object.FLOAT_OBJ},
+		{"float(\"1.25\");", "1.250000", object.FLOAT_OBJ},
+		{"char(98);", "b", object.CHAR_OBJ},
+		{"char(\"é\");", "é", object.CHAR_OBJ},
+		{"char(\"ab\");", "ERROR: 1:1: char: cannot convert \"ab\" to CHAR", object.ERROR_OBJ},
+		{"int x = int(2.5) + 1;", "3", object.INT_OBJ},
+
+		{"int len(string s) { return 0; }", "int len(string s) { return 0; }", object.FN_OBJ},
+		{"len(\"abc\");", "0", object.INT_OBJ},
+	})
+}
+
+func TestEvalPrint(t *testing.T) {
+	var out strings.Builder
+	e := New()
+	e.SetOutput(&out)
+
+	input := `print("a", 1); println(); println("b", 2.5, 'c', [1, 2]);`
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if p.HasErrors() {
+		t.Fatalf("parse errors: %v", p.Errors())
+	}
+
+	result := e.Eval(program)
+	if result.Type() != object.NULL_OBJ {
+		t.Fatalf("expected %s, got %s", object.NULL_OBJ, result.Inspect())
+	}
+
+	expected := "a 1\nb 2.500000 c int[2] [1, 2]\n"
+	if out.String() != expected {
+		t.Errorf("expected output %q, got %q", expected, out.String())
+	}
+}
+
+func TestEvalRegisterBuiltin(t *testing.T) {
+	e := New()
+	e.RegisterBuiltin("double", func(args ...object.Object) object.Object {
+		return &object.Integer{Value: args[0].(*object.Integer).Value * 2}
+	})
+
+	p := parser.New(lexer.New("double(21);"))
+	result := e.Eval(p.ParseProgram())
+	if result.Inspect() != "42" {
+		t.Errorf("expected 42, got %s", result.Inspect())
+	}
+}
+
+func evalWith(t *testing.T, e *Evaluator, input string) object.Object {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if p.HasErrors() {
+		t.Fatalf("parse errors for %q: %v", input, p.Errors())
+	}
+	return e.Eval(program)
+}
+
+func TestEvalLimits(t *testing.T) {
+	e := New(WithMaxSteps(1000))
+	result := evalWith(t, e, "int i = 0; while (true) { i++; }")
+	errObj, ok := result.(*object.Error)
+	if !ok || !errors.Is(errObj, ErrStepLimit) {
+		t.Fatalf("expected step limit error, got %s", result.Inspect())
+	}
+	if errObj.Message != "execution stopped: step limit exceeded" {
+		t.Errorf("unexpected message %q", errObj.Message)
+	}
+
+	// the budget is per evaluation
+	result = evalWith(t, e, "i;")
+	if result.Type() != object.INT_OBJ {
+		t.Errorf("expected INT, got %s", result.Inspect())
+	}
+
+	e = New(WithMaxCallDepth(50))
+	result = evalWith(t, e, "int f(int n) { if (n == 0) { return 0; } return f(n - 1); } f(49);")
+	if result.Inspect() != "0" {
+		t.Errorf("expected 0, got %s", result.Inspect())
+	}
+	result = evalWith(t, e, "f(50);")
+	if errObj, ok := result.(*object.Error); !ok || !errors.Is(errObj, ErrCallDepthLimit) {
+		t.Errorf("expected call depth limit error, got %s", result.Inspect())
+	}
+	result = e.Call("f", &object.Integer{Value: 50})
+	if errObj, ok := result.(*object.Error); !ok || !errors.Is(errObj, ErrCallDepthLimit) {
+		t.Errorf("expected call depth limit error, got %s", result.Inspect())
+	}
+
+	// the default depth stops infinite recursion before the Go stack overflows
+	e = New()
+	result = evalWith(t, e, "int g(int n) { return g(n + 1); } g(0);")
+	if errObj, ok := result.(*object.Error); !ok || !errors.Is(errObj, ErrCallDepthLimit) {
+		t.Errorf("expected call depth limit error, got %s", result.Inspect())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	e = New(WithContext(ctx))
+	result = evalWith(t, e, "while (true) { }")
+	if errObj, ok := result.(*object.Error); !ok || !errors.Is(errObj, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded error, got %s", result.Inspect())
+	}
+}
+
+func TestEvalMemoryLimits(t *testing.T) {
+	tests := []struct {
+		Option Option
+		Line   string
+		Err    error
+		Result string
+	}{
+		{WithMaxStringLength(8), "string s = \"ab\"; while (true) { s += s; }", object.ErrStringLimit,
+			"ERROR: 1:33: string length limit exceeded: 16 > 8"},
+		{WithMaxStringLength(8), "\"123456789\";", object.ErrStringLimit,
+			"ERROR: 1:1: string length limit exceeded: 9 > 8"},
+		{WithMaxCollectionSize(3), "int a[] = [1, 2, 3]; a + [4];", object.ErrCollectionLimit,
+			"ERROR: 1:24: collection size limit exceeded: 4 > 3"},
+		{WithMaxCollectionSize(3), "int a[] = []; while (true) { push(a, 1); }", object.ErrCollectionLimit,
+			"ERROR: 1:30: collection size limit exceeded: 4 > 3"},
+		{WithMaxCollectionSize(3), "int a[1000000000];", object.ErrCollectionLimit,
+			"ERROR: 1:1: collection size limit exceeded: 1000000000 > 3"},
+		{WithMaxCollectionSize(2), "dict d = {}; d[\"a\"] = 1; d[\"b\"] = 2; d[\"a\"] = 3; d[\"c\"] = 4;", object.ErrCollectionLimit,
+			"ERROR: 1:50: collection size limit exceeded: 3 > 2"},
+		{WithMaxCollectionSize(2), "{\"a\": 1, \"b\": 2} + {\"c\": 3};", object.ErrCollectionLimit,
+			"ERROR: 1:18: collection size limit exceeded: 3 > 2"},
+		{WithMaxMemory(1024), "string s = \"\"; while (true) { s += \"abcdefgh\"; }", object.ErrMemoryLimit, ""},
+		{WithMaxMemory(1024), "int i = 0; while (i < 100000) { i++; }", nil, "null"},
+	}
+
+	for _, tt := range tests {
+		e := New(tt.Option)
+		result := evalWith(t, e, tt.Line)
+
+		if tt.Err == nil {
+			if result.Inspect() != tt.Result {
+				t.Errorf("expected %q, got %q for %q", tt.Result, result.Inspect(), tt.Line)
+			}
+			continue
+		}
+
+		errObj, ok := result.(*object.Error)
+		if !ok || !errors.Is(errObj, tt.Err) {
+			t.Errorf("expected %v, got %s for %q", tt.Err, result.Inspect(), tt.Line)
+			continue
+		}
+		if tt.Result != "" && errObj.Inspect() != tt.Result {
+			t.Errorf("expected %q, got %q for %q", tt.Result, errObj.Inspect(), tt.Line)
+		}
+	}
+
+	// usage is accounted per evaluation
+	e := New(WithMaxMemory(100))
+	for i := 0; i < 10; i++ {
+		result := evalWith(t, e, "\"0123456789\" + \"0123456789\";")
+		if result.Type() != object.STR_OBJ {
+			t.Fatalf("expected STRING, got %s", result.Inspect())
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/menxqk/my-interpreter/object"
+)
+
+// DefaultMaxCallDepth keeps deep recursion from overflowing the Go stack
+const DefaultMaxCallDepth = 10000
+
+var (
+	ErrStepLimit      = errors.New("step limit exceeded")
+	ErrCallDepthLimit = errors.New("call depth limit exceeded")
+)
+
+// Option configures an Evaluator
+type Option func(*Evaluator)
+
+// WithMaxSteps limits the number of nodes evaluated by each call to Eval
+// or Call, n <= 0 means no limit
+func WithMaxSteps(n int) Option {
+	return func(e *Evaluator) {
+		e.maxSteps = n
+	}
+}
+
+// WithMaxCallDepth limits how deeply function calls can nest, n <= 0
+// means no limit
+func WithMaxCallDepth(n int) Option {
+	return func(e *Evaluator) {
+		e.maxCallDepth = n
+	}
+}
+
+// WithContext stops evaluation once ctx is done
+func WithContext(ctx context.Context) Option {
+	return func(e *Evaluator) {
+		e.ctx = ctx
+	}
+}
+
+// WithMaxMemory limits the approximate number of bytes allocated by the
+// strings, arrays and dicts created by each call to Eval or Call
+func WithMaxMemory(bytes int64) Option {
+	return func(e *Evaluator) {
+		e.memory().MaxBytes = bytes
+	}
+}
+
+// WithMaxStringLength limits the length in bytes of strings
+func WithMaxStringLength(n int) Option {
+	return func(e *Evaluator) {
+		e.memory().MaxStringLen = n
+	}
+}
+
+// WithMaxCollectionSize limits the number of elements of arrays and dicts
+func WithMaxCollectionSize(n int) Option {
+	return func(e *Evaluator) {
+		e.memory().MaxCollectionSize = n
+	}
+}
+
+func (e *Evaluator) memory() *object.Memory {
+	if e.mem == nil {
+		e.mem = &object.Memory{}
+	}
+	return e.mem
+}
+
+// alloc accounts for obj, a newly created object, and returns it or the
+// error for the memory limit it exceeds
+func (e *Evaluator) alloc(obj object.Object) object.Object {
+	if errObj := e.mem.Alloc(obj); errObj != nil {
+		return errObj
+	}
+	return obj
+}
+
+// SetContext replaces the context checked during evaluation
+func (e *Evaluator) SetContext(ctx context.Context) {
+	e.ctx = ctx
+}
+
+// step accounts for the evaluation of one node and reports an error once
+// a limit is exceeded
+func (e *Evaluator) step() *object.Error {
+	e.steps++
+	if e.maxSteps > 0 && e.steps > e.maxSteps {
+		return newLimitError(ErrStepLimit)
+	}
+
+	if e.ctx != nil {
+		select {
+		case <-e.ctx.Done():
+			return newLimitError(e.ctx.Err())
+		default:
+		}
+	}
+
+	return nil
+}
+
+// enterCall accounts for a function call, leaveCall must follow it
+func (e *Evaluator) enterCall() *object.Error {
+	e.callDepth++
+	if e.maxCallDepth > 0 && e.callDepth > e.maxCallDepth {
+		return newLimitError(ErrCallDepthLimit)
+	}
+	return nil
+}
+
+func (e *Evaluator) leaveCall() {
+	e.callDepth--
+}
+
+func newLimitError(err error) *object.Error {
+	return &object.Error{Message: "execution stopped: " + err.Error(), Err: err}
+}
@@ -0,0 +1,25 @@
+package interpreter
+
+import (
+	"reflect"
+
+	"github.com/menxqk/my-interpreter/object"
+)
+
+// fromObject converts an interpreter object to its natural Go value
+func fromObject(obj object.Object) any {
+	var v any
+	if err := object.ToGo(obj, &v); err != nil {
+		return obj
+	}
+	return v
+}
+
+// toValue converts an interpreter object to a Go value of type t
+func toValue(obj object.Object, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t)
+	if err := object.ToGo(obj, v.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return v.Elem(), nil
+}
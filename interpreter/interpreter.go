@@ -0,0 +1,247 @@
+// Package interpreter runs programs from Go code, converting values
+// between Go and the interpreter as they cross the boundary.
+package interpreter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/menxqk/my-interpreter/evaluator"
+	"github.com/menxqk/my-interpreter/lexer"
+	"github.com/menxqk/my-interpreter/object"
+	"github.com/menxqk/my-interpreter/parser"
+	"github.com/menxqk/my-interpreter/token"
+	"github.com/menxqk/my-interpreter/typecheck"
+)
+
+// ParseError holds the errors found while parsing a program
+type ParseError struct {
+	File   string
+	Errors []string
+}
+
+func (e *ParseError) Error() string {
+	if e.File == "" {
+		return strings.Join(e.Errors, "\n")
+	}
+	errors := []string{}
+	for _, err := range e.Errors {
+		errors = append(errors, e.File+":"+err)
+	}
+	return strings.Join(errors, "\n")
+}
+
+// TypeError holds the errors found while type checking a program
+type TypeError struct {
+	File   string
+	Errors []string
+}
+
+func (e *TypeError) Error() string {
+	if e.File == "" {
+		return strings.Join(e.Errors, "\n")
+	}
+	errors := []string{}
+	for _, err := range e.Errors {
+		errors = append(errors, e.File+":"+err)
+	}
+	return strings.Join(errors, "\n")
+}
+
+// RuntimeError is an error produced while evaluating a program, Err
+// holds its cause when it has one, as when a limit is exceeded
+type RuntimeError struct {
+	File    string
+	Pos     token.Position
+	Message string
+	Err     error
+}
+
+func (e *RuntimeError) Error() string {
+	// errors of calls from the host have no position in the source
+	if !e.Pos.IsValid() {
+		if e.File == "" {
+			return e.Message
+		}
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+	if e.File == "" {
+		return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+	}
+	return fmt.Sprintf("%s:%s: %s", e.File, e.Pos, e.Message)
+}
+
+func (e *RuntimeError) Unwrap() error { return e.Err }
+
+// Interpreter keeps its globals across calls to Run, RunFile and Call
+type Interpreter struct {
+	eval    *evaluator.Evaluator
+	checker *typecheck.Checker
+}
+
+func New(opts ...evaluator.Option) *Interpreter {
+	return &Interpreter{eval: evaluator.New(opts...), checker: typecheck.New()}
+}
+
+// SetOutput sets the destination of print and println, os.Stdout by default
+func (i *Interpreter) SetOutput(w io.Writer) {
+	i.eval.SetOutput(w)
+}
+
+// Run executes src and returns the value of its last statement
+func (i *Interpreter) Run(src string) (any, error) {
+	return i.run("", src)
+}
+
+// RunFile executes the program in the file at path
+func (i *Interpreter) RunFile(path string) (any, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return i.run(path, string(src))
+}
+
+func (i *Interpreter) run(file string, src string) (any, error) {
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if p.HasErrors() {
+		return nil, &ParseError{File: file, Errors: p.Errors()}
+	}
+
+	if errs := i.checker.Check(program); len(errs) > 0 {
+		typeErr := &TypeError{File: file}
+		for _, err := range errs {
+			typeErr.Errors = append(typeErr.Errors, err.Error())
+		}
+		return nil, typeErr
+	}
+
+	result := i.eval.Eval(program)
+	if errObj, ok := result.(*object.Error); ok {
+		i.checker.Rollback()
+		return nil, &RuntimeError{File: file, Pos: errObj.Pos, Message: errObj.Message, Err: errObj.Err}
+	}
+
+	return fromObject(result), nil
+}
+
+// Set declares or replaces the global name with value
+func (i *Interpreter) Set(name string, value any) error {
+	obj, err := object.FromGo(value)
+	if err != nil {
+		return fmt.Errorf("set %q: %w", name, err)
+	}
+	i.eval.Set(name, obj)
+	i.checker.Declare(name, typecheck.TypeOfObject(obj))
+	return nil
+}
+
+// Get returns the value of the global name
+func (i *Interpreter) Get(name string) (any, bool) {
+	obj, ok := i.eval.Get(name)
+	if !ok {
+		return nil, false
+	}
+	return fromObject(obj), true
+}
+
+// Call calls the script function fnName with args and returns its result
+func (i *Interpreter) Call(fnName string, args ...any) (any, error) {
+	objArgs := []object.Object{}
+	for n, arg := range args {
+		obj, err := object.FromGo(arg)
+		if err != nil {
+			return nil, fmt.Errorf("call %q: argument %d: %w", fnName, n+1, err)
+		}
+		objArgs = append(objArgs, obj)
+	}
+
+	result := i.eval.Call(fnName, objArgs...)
+	if errObj, ok := result.(*object.Error); ok {
+		return nil, &RuntimeError{Pos: errObj.Pos, Message: errObj.Message, Err: errObj.Err}
+	}
+
+	return fromObject(result), nil
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Register makes the Go function fn callable from scripts as name. fn may
+// return nothing, a value, an error, or a value and an error; a non-nil
+// error becomes a runtime error in the script.
+func (i *Interpreter) Register(name string, fn any) error {
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func {
+		return fmt.Errorf("register %q: expected a function, got %T", name, fn)
+	}
+
+	fnType := fnValue.Type()
+	switch {
+	case fnType.NumOut() > 2:
+		return fmt.Errorf("register %q: too many results", name)
+	case fnType.NumOut() == 2 && fnType.Out(1) != errorType:
+		return fmt.Errorf("register %q: second result must be an error", name)
+	}
+
+	i.eval.RegisterBuiltin(name, func(args ...object.Object) object.Object {
+		in, err := convertArgs(fnType, args)
+		if err != nil {
+			return &object.Error{Message: fmt.Sprintf("%s: %s", name, err)}
+		}
+
+		out := fnValue.Call(in)
+
+		if len(out) > 0 && out[len(out)-1].Type() == errorType {
+			if err, _ := out[len(out)-1].Interface().(error); err != nil {
+				return &object.Error{Message: fmt.Sprintf("%s: %s", name, err)}
+			}
+			out = out[:len(out)-1]
+		}
+		if len(out) == 0 {
+			return &object.Null{}
+		}
+
+		obj, err := object.FromGo(out[0].Interface())
+		if err != nil {
+			return &object.Error{Message: fmt.Sprintf("%s: result: %s", name, err)}
+		}
+		return obj
+	})
+	// arguments are converted when the function is called
+	i.checker.Declare(name, typecheck.Unknown)
+
+	return nil
+}
+
+func convertArgs(fnType reflect.Type, args []object.Object) ([]reflect.Value, error) {
+	numIn := fnType.NumIn()
+	if fnType.IsVariadic() {
+		if len(args) < numIn-1 {
+			return nil, fmt.Errorf("wrong number of arguments: %d, expected at least %d", len(args), numIn-1)
+		}
+	} else if len(args) != numIn {
+		return nil, fmt.Errorf("wrong number of arguments: %d, expected %d", len(args), numIn)
+	}
+
+	in := []reflect.Value{}
+	for n, arg := range args {
+		var argType reflect.Type
+		if fnType.IsVariadic() && n >= numIn-1 {
+			argType = fnType.In(numIn - 1).Elem()
+		} else {
+			argType = fnType.In(n)
+		}
+
+		value, err := toValue(arg, argType)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", n+1, err)
+		}
+		in = append(in, value)
+	}
+
+	return in, nil
+}
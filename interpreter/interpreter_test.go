@@ -0,0 +1,281 @@
+package interpreter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/menxqk/my-interpreter/evaluator"
+)
+
+func TestRun(t *testing.T) {
+	i := New()
+
+	result, err := i.Run("int x = 20; x + 22;")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != int64(42) {
+		t.Errorf("expected 42, got %v", result)
+	}
+
+	// globals persist across runs
+	result, err = i.Run("x * 2;")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != int64(40) {
+		t.Errorf("expected 40, got %v", result)
+	}
+
+	_, err = i.Run("int y = ;")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected *ParseError, got %T", err)
+	}
+
+	// type errors stop the program before it runs
+	_, err = i.Run("int q = undefinedName; q;")
+	var typeErr *TypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected *TypeError, got %T", err)
+	}
+	if err.Error() != "1:9: undeclared name \"undefinedName\"" {
+		t.Errorf("unexpected error message %q", err.Error())
+	}
+	if _, err = i.Run("q;"); !errors.As(err, &typeErr) {
+		t.Errorf("expected *TypeError for a global of a program that did not run, got %v", err)
+	}
+
+	_, err = i.Run("int z = 1;\nz / 0;")
+	var runtimeErr *RuntimeError
+	if !errors.As(err, &runtimeErr) {
+		t.Fatalf("expected *RuntimeError, got %T", err)
+	}
+	if err.Error() != "2:3: division by zero: 1 / 0" {
+		t.Errorf("unexpected error message %q", err.Error())
+	}
+
+	// neither are the globals of a program that failed to run
+	if _, err = i.Run("int w = 1 / 0;"); !errors.As(err, &runtimeErr) {
+		t.Fatalf("expected *RuntimeError, got %T", err)
+	}
+	if _, err = i.Run("w;"); !errors.As(err, &typeErr) {
+		t.Errorf("expected *TypeError for a global of a program that failed, got %v", err)
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.src")
+	if err := os.WriteFile(path, []byte("string s = \"a\" + \"b\";\nint zero = 0;\n1 / zero;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	i := New()
+	_, err := i.RunFile(path)
+	if err == nil || !strings.HasPrefix(err.Error(), path+":3:3: ") {
+		t.Fatalf("expected runtime error in %s, got %v", path, err)
+	}
+
+	s, ok := i.Get("s")
+	if !ok || s != "ab" {
+		t.Errorf("expected \"ab\", got %v", s)
+	}
+}
+
+func TestSetGet(t *testing.T) {
+	i := New()
+
+	values := map[string]any{
+		"n":     7,
+		"f":     1.5,
+		"c":     'x',
+		"s":     "str",
+		"b":     true,
+		"list":  []any{"a", "b"},
+		"table": map[string]any{"k": int64(1)},
+		"queue": []any{},
+	}
+	for name, value := range values {
+		if err := i.Set(name, value); err != nil {
+			t.Fatalf("Set(%q): %s", name, err)
+		}
+	}
+
+	if _, err := i.Run("n += 1; list[1] = \"z\"; push(queue, 3);"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]any{
+		"n":     int64(8),
+		"f":     1.5,
+		"c":     'x',
+		"s":     "str",
+		"b":     true,
+		"list":  []any{"a", "z"},
+		"table": map[string]any{"k": int64(1)},
+		"queue": []any{int64(3)},
+	}
+	for name, value := range expected {
+		got, ok := i.Get(name)
+		if !ok {
+			t.Fatalf("Get(%q): not found", name)
+		}
+		if !reflect.DeepEqual(got, value) {
+			t.Errorf("Get(%q): expected %#v, got %#v", name, value, got)
+		}
+	}
+
+	// globals set by the host are known to the type checker
+	_, err := i.Run("s = 1;")
+	if err == nil || err.Error() != "1:5: cannot assign INT to STRING" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, ok := i.Get("missing"); ok {
+		t.Errorf("expected missing global")
+	}
+	if err := i.Set("mixed", []any{1, "a"}); err == nil {
+		t.Errorf("expected error for mixed array")
+	}
+	if err := i.Set("ch", make(chan int)); err == nil {
+		t.Errorf("expected error for channel")
+	}
+}
+
+func TestCall(t *testing.T) {
+	i := New()
+	if _, err := i.Run("int add(int a, int b) { return a + b; }"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, err := i.Call("add", 2, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != int64(5) {
+		t.Errorf("expected 5, got %v", result)
+	}
+
+	if _, err := i.Run("void noop() { }"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	result, err = i.Call("noop")
+	if err != nil || result != nil {
+		t.Errorf("expected nil, got %#v (%v)", result, err)
+	}
+
+	_, err = i.Call("add", 2, "3")
+	if err == nil || err.Error() != "wrong type for argument 2, got=STRING; expected:INT" {
+		t.Errorf("unexpected error %v", err)
+	}
+	_, err = i.Call("missing")
+	if err == nil || err.Error() != "\"missing\" function not found" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	result, err = i.Call("len", "abc")
+	if err != nil || result != int64(3) {
+		t.Errorf("expected 3, got %v (%v)", result, err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	i := New()
+
+	var logged []string
+	err := i.Register("log", func(msg string) { logged = append(logged, msg) })
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err = i.Register("sum", func(nums ...int) int {
+		total := 0
+		for _, n := range nums {
+			total += n
+		}
+		return total
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err = i.Register("half", func(n float64) (float64, error) {
+		if n < 0 {
+			return 0, errors.New("negative number")
+		}
+		return n / 2, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, err := i.Run("log(\"hello\"); sum(1, 2, 3) + half(3.0);")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != 7.5 {
+		t.Errorf("expected 7.5, got %v", result)
+	}
+	if !reflect.DeepEqual(logged, []string{"hello"}) {
+		t.Errorf("expected log of hello, got %v", logged)
+	}
+
+	_, err = i.Run("half(-1.0);")
+	if err == nil || err.Error() != "1:1: half: negative number" {
+		t.Errorf("unexpected error %v", err)
+	}
+	_, err = i.Run("log(1);")
+	if err == nil || err.Error() != "1:1: log: argument 1: cannot convert INT to string" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := i.Register("bad", 1); err == nil {
+		t.Errorf("expected error registering a non-function")
+	}
+	if err := i.Register("bad", func() (int, int) { return 0, 0 }); err == nil {
+		t.Errorf("expected error registering a function without an error result")
+	}
+}
+
+func TestRegisterStructs(t *testing.T) {
+	type user struct {
+		Name string `interp:"name"`
+		Age  int    `interp:"age"`
+	}
+
+	i := New()
+	err := i.Register("older", func(u user, years int) user {
+		u.Age += years
+		return u
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := i.Set("ann", user{Name: "Ann", Age: 30}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, err := i.Run("dict u = older(ann, 2); u[\"age\"];")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != int64(32) {
+		t.Errorf("expected 32, got %v", result)
+	}
+
+	_, err = i.Run("older({\"age\": \"old\"}, 1);")
+	if err == nil || err.Error() != "1:1: older: argument 1: [\"age\"]: cannot convert STRING to int" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	i := New(evaluator.WithMaxSteps(100))
+
+	_, err := i.Run("while (true) { }")
+	if !errors.Is(err, evaluator.ErrStepLimit) {
+		t.Errorf("expected step limit error, got %v", err)
+	}
+}
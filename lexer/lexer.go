@@ -3,6 +3,7 @@ package lexer
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/menxqk/my-interpreter/token"
 )
@@ -15,6 +16,11 @@ type Lexer struct {
 
 	char rune
 
+	// position of char in the input
+	offset int
+	line   int
+	column int
+
 	debug bool
 }
 
@@ -25,7 +31,7 @@ func New(input string, debug ...bool) *Lexer {
 		d = debug[0]
 	}
 
-	l := &Lexer{input: []rune(input), debug: d}
+	l := &Lexer{input: []rune(input), debug: d, line: 1, column: 1}
 	l.advancePos()
 
 	return l
@@ -36,15 +42,65 @@ func (l *Lexer) NextToken() token.Token {
 
 	l.skipWhiteSpace()
 
+	pos := l.position()
+
 	switch l.char {
 	case '+':
-		tok = newToken(token.PLUS, string(l.char))
+		if l.nextTokenIs('+') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.INCREMENT, string(ch)+string(l.char))
+		} else if l.nextTokenIs('=') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.PLUS_ASSIGN, string(ch)+string(l.char))
+		} else {
+			tok = newToken(token.PLUS, string(l.char))
+		}
 	case '-':
-		tok = newToken(token.MINUS, string(l.char))
+		if l.nextTokenIs('-') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.DECREMENT, string(ch)+string(l.char))
+		} else if l.nextTokenIs('=') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.MINUS_ASSIGN, string(ch)+string(l.char))
+		} else {
+			tok = newToken(token.MINUS, string(l.char))
+		}
 	case '/':
-		tok = newToken(token.SLASH, string(l.char))
+		if l.nextTokenIs('=') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.SLASH_ASSIGN, string(ch)+string(l.char))
+		} else {
+			tok = newToken(token.SLASH, string(l.char))
+		}
 	case '*':
-		tok = newToken(token.ASTERISK, string(l.char))
+		if l.nextTokenIs('*') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.POWER, string(ch)+string(l.char))
+		} else if l.nextTokenIs('=') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.ASTERISK_ASSIGN, string(ch)+string(l.char))
+		} else {
+			tok = newToken(token.ASTERISK, string(l.char))
+		}
+	case '%':
+		if l.nextTokenIs('=') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.PERCENT_ASSIGN, string(ch)+string(l.char))
+		} else {
+			tok = newToken(token.PERCENT, string(l.char))
+		}
+	case '~':
+		tok = newToken(token.TILDE, string(l.char))
+	case '^':
+		tok = newToken(token.CARET, string(l.char))
 	case '!':
 		if l.nextTokenIs('=') {
 			ch := l.char
@@ -66,6 +122,10 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.char
 			l.advancePos()
 			tok = newToken(token.LTE, string(ch)+string(l.char))
+		} else if l.nextTokenIs('<') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.LSHIFT, string(ch)+string(l.char))
 		} else {
 			tok = newToken(token.LT, string(l.char))
 		}
@@ -74,9 +134,29 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.char
 			l.advancePos()
 			tok = newToken(token.GTE, string(ch)+string(l.char))
+		} else if l.nextTokenIs('>') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.RSHIFT, string(ch)+string(l.char))
 		} else {
 			tok = newToken(token.GT, string(l.char))
 		}
+	case '&':
+		if l.nextTokenIs('&') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.AND, string(ch)+string(l.char))
+		} else {
+			tok = newToken(token.AMPERSAND, string(l.char))
+		}
+	case '|':
+		if l.nextTokenIs('|') {
+			ch := l.char
+			l.advancePos()
+			tok = newToken(token.OR, string(ch)+string(l.char))
+		} else {
+			tok = newToken(token.PIPE, string(l.char))
+		}
 	case ',':
 		tok = newToken(token.COMMA, string(l.char))
 	case ';':
@@ -123,6 +203,9 @@ func (l *Lexer) NextToken() token.Token {
 	}
 	l.advancePos()
 
+	tok.Pos = pos
+	tok.End = l.position()
+
 	// DEBUG INFO
 	if l.debug {
 		fmt.Printf("token: %+v\n", tok)
@@ -132,6 +215,16 @@ func (l *Lexer) NextToken() token.Token {
 }
 
 func (l *Lexer) advancePos() {
+	// move the position past the current character
+	if l.char != 0 {
+		l.offset += utf8.RuneLen(l.char)
+		l.column++
+		if l.char == '\n' {
+			l.line++
+			l.column = 1
+		}
+	}
+
 	l.curPos = l.nextPos
 	if l.nextPos >= len(l.input) {
 		l.char = 0
@@ -141,6 +234,10 @@ func (l *Lexer) advancePos() {
 	}
 }
 
+func (l *Lexer) position() token.Position {
+	return token.Position{Offset: l.offset, Line: l.line, Column: l.column}
+}
+
 func (l *Lexer) nextChar() rune {
 	if l.nextPos >= len(l.input) {
 		return 0
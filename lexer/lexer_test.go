@@ -7,9 +7,12 @@ import (
 )
 
 func TestNextToken(t *testing.T) {
-	input := `| abc int float char string dict
+	input := `@ abc int float char string dict bool void func
+	if else return true false null while for break continue in
 	10 35.50 'c' "A string"
-	+ - / * ! = == != < <= > >= 
+	+ - / * ! = == != < <= > >= && ||
+	% ** ~ & | ^ << >>
+	+= -= *= /= %= ++ --
 	, ; : ( ) [ ] { }
 	`
 
@@ -17,13 +20,28 @@ func TestNextToken(t *testing.T) {
 		Type    string
 		Literal string
 	}{
-		{token.ILLEGAL, "|"},
+		{token.ILLEGAL, "@"},
 		{token.IDENT, "abc"},
 		{token.INT_TYPE, "int"},
 		{token.FLOAT_TYPE, "float"},
 		{token.CHAR_TYPE, "char"},
 		{token.STRING_TYPE, "string"},
 		{token.DICT_TYPE, "dict"},
+		{token.BOOL_TYPE, "bool"},
+		{token.VOID_TYPE, "void"},
+		{token.FUNC_TYPE, "func"},
+
+		{token.IF, "if"},
+		{token.ELSE, "else"},
+		{token.RETURN, "return"},
+		{token.TRUE, "true"},
+		{token.FALSE, "false"},
+		{token.NULL, "null"},
+		{token.WHILE, "while"},
+		{token.FOR, "for"},
+		{token.BREAK, "break"},
+		{token.CONTINUE, "continue"},
+		{token.IN, "in"},
 
 		{token.INT_VALUE, "10"},
 		{token.FLOAT_VALUE, "35.50"},
@@ -42,6 +60,23 @@ func TestNextToken(t *testing.T) {
 		{token.LTE, "<="},
 		{token.GT, ">"},
 		{token.GTE, ">="},
+		{token.AND, "&&"},
+		{token.OR, "||"},
+		{token.PERCENT, "%"},
+		{token.POWER, "**"},
+		{token.TILDE, "~"},
+		{token.AMPERSAND, "&"},
+		{token.PIPE, "|"},
+		{token.CARET, "^"},
+		{token.LSHIFT, "<<"},
+		{token.RSHIFT, ">>"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.MINUS_ASSIGN, "-="},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.SLASH_ASSIGN, "/="},
+		{token.PERCENT_ASSIGN, "%="},
+		{token.INCREMENT, "++"},
+		{token.DECREMENT, "--"},
 
 		{token.COMMA, ","},
 		{token.SEMICOLON, ";"},
@@ -151,3 +186,44 @@ func TestIllegalString(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenPosition(t *testing.T) {
+	input := "int x = 10;\n  string s = \"é\";\nx"
+
+	tests := []struct {
+		Literal string
+		Pos     token.Position
+		End     token.Position
+	}{
+		{"int", token.Position{Offset: 0, Line: 1, Column: 1}, token.Position{Offset: 3, Line: 1, Column: 4}},
+		{"x", token.Position{Offset: 4, Line: 1, Column: 5}, token.Position{Offset: 5, Line: 1, Column: 6}},
+		{"=", token.Position{Offset: 6, Line: 1, Column: 7}, token.Position{Offset: 7, Line: 1, Column: 8}},
+		{"10", token.Position{Offset: 8, Line: 1, Column: 9}, token.Position{Offset: 10, Line: 1, Column: 11}},
+		{";", token.Position{Offset: 10, Line: 1, Column: 11}, token.Position{Offset: 11, Line: 1, Column: 12}},
+		{"string", token.Position{Offset: 14, Line: 2, Column: 3}, token.Position{Offset: 20, Line: 2, Column: 9}},
+		{"s", token.Position{Offset: 21, Line: 2, Column: 10}, token.Position{Offset: 22, Line: 2, Column: 11}},
+		{"=", token.Position{Offset: 23, Line: 2, Column: 12}, token.Position{Offset: 24, Line: 2, Column: 13}},
+		{"é", token.Position{Offset: 25, Line: 2, Column: 14}, token.Position{Offset: 29, Line: 2, Column: 17}},
+		{";", token.Position{Offset: 29, Line: 2, Column: 17}, token.Position{Offset: 30, Line: 2, Column: 18}},
+		{"x", token.Position{Offset: 31, Line: 3, Column: 1}, token.Position{Offset: 32, Line: 3, Column: 2}},
+		{"EOF", token.Position{Offset: 32, Line: 3, Column: 2}, token.Position{Offset: 32, Line: 3, Column: 2}},
+	}
+
+	l := New(input)
+
+	for _, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.Literal {
+			t.Fatalf("expected literal %q, got=%q", tt.Literal, tok.Literal)
+		}
+
+		if tok.Pos != tt.Pos {
+			t.Fatalf("expected position %+v for %q, got=%+v", tt.Pos, tt.Literal, tok.Pos)
+		}
+
+		if tok.End != tt.End {
+			t.Fatalf("expected end %+v for %q, got=%+v", tt.End, tt.Literal, tok.End)
+		}
+	}
+}
@@ -3,15 +3,103 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
+	"os"
 
+	"github.com/menxqk/my-interpreter/evaluator"
+	"github.com/menxqk/my-interpreter/lexer"
+	"github.com/menxqk/my-interpreter/object"
+	"github.com/menxqk/my-interpreter/parser"
 	"github.com/menxqk/my-interpreter/repl"
+	"github.com/menxqk/my-interpreter/typecheck"
 )
 
 func main() {
-	fmt.Println("My 'C-like' interpreter")
-
 	debug := flag.Bool("debug", false, "Show debug information")
+	code := flag.String("e", "", "Execute the given code")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [script [args...]]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	repl.Start(*debug)
+	switch {
+	case *code != "":
+		os.Exit(run("-e", *code, flag.Args(), *debug))
+	case flag.NArg() > 0 && flag.Arg(0) != "-":
+		os.Exit(runFile(flag.Arg(0), flag.Args()[1:], *debug))
+	case flag.NArg() > 0 || !isTerminal(os.Stdin):
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not read stdin: %s\n", err)
+			os.Exit(1)
+		}
+		var args []string
+		if flag.NArg() > 0 {
+			args = flag.Args()[1:]
+		}
+		os.Exit(run("<stdin>", string(src), args, *debug))
+	default:
+		fmt.Println("My 'C-like' interpreter")
+		repl.Start(*debug)
+	}
+}
+
+func runFile(path string, args []string, debug bool) int {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not read file: %s\n", err)
+		return 1
+	}
+	return run(path, string(src), args, debug)
+}
+
+// run executes src as a whole program and returns the exit status
+func run(name string, src string, args []string, debug bool) int {
+	l := lexer.New(src, debug)
+	p := parser.New(l, debug)
+	program := p.ParseProgram()
+
+	if p.HasErrors() {
+		for _, d := range p.Diagnostics() {
+			d.Render(os.Stderr, name, src)
+		}
+		return 1
+	}
+
+	checker := typecheck.New()
+	checker.Declare("args", typecheck.ArrayOf(typecheck.String))
+	if errs := checker.Check(program); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s:%s: %s\n", name, err.Pos, err.Message)
+		}
+		return 1
+	}
+
+	eval := evaluator.New()
+	eval.Set("args", newArgsArray(args))
+
+	res := eval.Eval(program)
+	if errObj, ok := res.(*object.Error); ok {
+		fmt.Fprintf(os.Stderr, "%s:%s: %s\n", name, errObj.Pos, errObj.Message)
+		return 1
+	}
+
+	return 0
+}
+
+func newArgsArray(args []string) *object.Array {
+	elems := []object.Object{}
+	for _, arg := range args {
+		elems = append(elems, &object.String{Value: arg})
+	}
+	return &object.Array{ArrType: object.STR_OBJ, Size: len(elems), Elements: elems}
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return true
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
 }
@@ -35,7 +35,7 @@ func (a *Array) ToType(objType ObjectType) Object {
 func (a *Array) Add(o Object) Object {
 	oArr := o.(*Array)
 
-	elems := a.Elements[:]
+	elems := append([]Object{}, a.Elements...)
 	for _, elem := range oArr.Elements {
 		addElem := true
 		for _, e := range a.Elements {
@@ -49,7 +49,11 @@ func (a *Array) Add(o Object) Object {
 		}
 	}
 
-	return &Array{ArrType: a.ArrType, Elements: elems, Size: len(elems)}
+	arrType := a.ArrType
+	if arrType == ANY_OBJ {
+		arrType = o.(*Array).ArrType
+	}
+	return &Array{ArrType: arrType, Elements: elems, Size: len(elems)}
 }
 func (a *Array) Sub(o Object) Object {
 	oArr := o.(*Array)
@@ -70,8 +74,15 @@ func (a *Array) Sub(o Object) Object {
 
 	return &Array{ArrType: a.ArrType, Elements: elems, Size: len(elems)}
 }
-func (a *Array) Mul(o Object) Object { return &Null{} }
-func (a *Array) Div(o Object) Object { return &Null{} }
+func (a *Array) Mul(o Object) Object    { return &Null{} }
+func (a *Array) Div(o Object) Object    { return &Null{} }
+func (a *Array) Mod(o Object) Object    { return &Null{} }
+func (a *Array) Pow(o Object) Object    { return &Null{} }
+func (a *Array) BitAnd(o Object) Object { return &Null{} }
+func (a *Array) BitOr(o Object) Object  { return &Null{} }
+func (a *Array) BitXor(o Object) Object { return &Null{} }
+func (a *Array) Shl(o Object) Object    { return &Null{} }
+func (a *Array) Shr(o Object) Object    { return &Null{} }
 func (a *Array) Equ(o Object) Object {
 	return &Boolean{Value: a == o.(*Array)}
 }
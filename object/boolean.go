@@ -13,6 +13,13 @@ func (b *Boolean) Add(o Object) Object              { return &Null{} }
 func (b *Boolean) Sub(o Object) Object              { return &Null{} }
 func (b *Boolean) Mul(o Object) Object              { return &Null{} }
 func (b *Boolean) Div(o Object) Object              { return &Null{} }
+func (b *Boolean) Mod(o Object) Object              { return &Null{} }
+func (b *Boolean) Pow(o Object) Object              { return &Null{} }
+func (b *Boolean) BitAnd(o Object) Object           { return &Null{} }
+func (b *Boolean) BitOr(o Object) Object            { return &Null{} }
+func (b *Boolean) BitXor(o Object) Object           { return &Null{} }
+func (b *Boolean) Shl(o Object) Object              { return &Null{} }
+func (b *Boolean) Shr(o Object) Object              { return &Null{} }
 func (b *Boolean) Equ(o Object) Object {
 	return &Boolean{Value: b.Value == o.(*Boolean).Value}
 }
@@ -0,0 +1,24 @@
+package object
+
+type Break struct{}
+
+func (b *Break) Type() string                     { return BREAK_OBJ }
+func (b *Break) Inspect() string                  { return "break" }
+func (b *Break) ToType(objType ObjectType) Object { return &Null{} }
+func (b *Break) Add(o Object) Object              { return &Null{} }
+func (b *Break) Sub(o Object) Object              { return &Null{} }
+func (b *Break) Mul(o Object) Object              { return &Null{} }
+func (b *Break) Div(o Object) Object              { return &Null{} }
+func (b *Break) Mod(o Object) Object              { return &Null{} }
+func (b *Break) Pow(o Object) Object              { return &Null{} }
+func (b *Break) BitAnd(o Object) Object           { return &Null{} }
+func (b *Break) BitOr(o Object) Object            { return &Null{} }
+func (b *Break) BitXor(o Object) Object           { return &Null{} }
+func (b *Break) Shl(o Object) Object              { return &Null{} }
+func (b *Break) Shr(o Object) Object              { return &Null{} }
+func (b *Break) Equ(o Object) Object              { return &Null{} }
+func (b *Break) NotEqu(o Object) Object           { return &Null{} }
+func (b *Break) Gt(o Object) Object               { return &Null{} }
+func (b *Break) Gte(o Object) Object              { return &Null{} }
+func (b *Break) Lt(o Object) Object               { return &Null{} }
+func (b *Break) Lte(o Object) Object              { return &Null{} }
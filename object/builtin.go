@@ -0,0 +1,38 @@
+package object
+
+import "fmt"
+
+// BuiltinFunction is the Go function behind a builtin
+type BuiltinFunction func(args ...Object) Object
+
+type Builtin struct {
+	Name string
+	Fn   BuiltinFunction
+}
+
+func (b *Builtin) Type() string                     { return BUILTIN_OBJ }
+func (b *Builtin) Inspect() string                  { return fmt.Sprintf("builtin %s", b.Name) }
+func (b *Builtin) ToType(objType ObjectType) Object { return &Null{} }
+func (b *Builtin) Add(o Object) Object              { return &Null{} }
+func (b *Builtin) Sub(o Object) Object              { return &Null{} }
+func (b *Builtin) Mul(o Object) Object              { return &Null{} }
+func (b *Builtin) Div(o Object) Object              { return &Null{} }
+func (b *Builtin) Mod(o Object) Object              { return &Null{} }
+func (b *Builtin) Pow(o Object) Object              { return &Null{} }
+func (b *Builtin) BitAnd(o Object) Object           { return &Null{} }
+func (b *Builtin) BitOr(o Object) Object            { return &Null{} }
+func (b *Builtin) BitXor(o Object) Object           { return &Null{} }
+func (b *Builtin) Shl(o Object) Object              { return &Null{} }
+func (b *Builtin) Shr(o Object) Object              { return &Null{} }
+func (b *Builtin) Equ(o Object) Object {
+	ob, ok := o.(*Builtin)
+	return &Boolean{Value: ok && b == ob}
+}
+func (b *Builtin) NotEqu(o Object) Object {
+	ob, ok := o.(*Builtin)
+	return &Boolean{Value: !ok || b != ob}
+}
+func (b *Builtin) Gt(o Object) Object  { return &Null{} }
+func (b *Builtin) Gte(o Object) Object { return &Null{} }
+func (b *Builtin) Lt(o Object) Object  { return &Null{} }
+func (b *Builtin) Lte(o Object) Object { return &Null{} }
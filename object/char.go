@@ -21,9 +21,16 @@ func (c *Char) ToType(objType ObjectType) Object {
 func (c *Char) Add(o Object) Object {
 	return &String{Value: string(c.Value) + o.(*String).Value}
 }
-func (c *Char) Sub(o Object) Object { return &Null{} }
-func (c *Char) Mul(o Object) Object { return &Null{} }
-func (c *Char) Div(o Object) Object { return &Null{} }
+func (c *Char) Sub(o Object) Object    { return &Null{} }
+func (c *Char) Mul(o Object) Object    { return &Null{} }
+func (c *Char) Div(o Object) Object    { return &Null{} }
+func (c *Char) Mod(o Object) Object    { return &Null{} }
+func (c *Char) Pow(o Object) Object    { return &Null{} }
+func (c *Char) BitAnd(o Object) Object { return &Null{} }
+func (c *Char) BitOr(o Object) Object  { return &Null{} }
+func (c *Char) BitXor(o Object) Object { return &Null{} }
+func (c *Char) Shl(o Object) Object    { return &Null{} }
+func (c *Char) Shr(o Object) Object    { return &Null{} }
 func (c *Char) Equ(o Object) Object {
 	return &Boolean{Value: c.Value == o.(*Char).Value}
 }
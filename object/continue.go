@@ -0,0 +1,24 @@
+package object
+
+type Continue struct{}
+
+func (c *Continue) Type() string                     { return CONTINUE_OBJ }
+func (c *Continue) Inspect() string                  { return "continue" }
+func (c *Continue) ToType(objType ObjectType) Object { return &Null{} }
+func (c *Continue) Add(o Object) Object              { return &Null{} }
+func (c *Continue) Sub(o Object) Object              { return &Null{} }
+func (c *Continue) Mul(o Object) Object              { return &Null{} }
+func (c *Continue) Div(o Object) Object              { return &Null{} }
+func (c *Continue) Mod(o Object) Object              { return &Null{} }
+func (c *Continue) Pow(o Object) Object              { return &Null{} }
+func (c *Continue) BitAnd(o Object) Object           { return &Null{} }
+func (c *Continue) BitOr(o Object) Object            { return &Null{} }
+func (c *Continue) BitXor(o Object) Object           { return &Null{} }
+func (c *Continue) Shl(o Object) Object              { return &Null{} }
+func (c *Continue) Shr(o Object) Object              { return &Null{} }
+func (c *Continue) Equ(o Object) Object              { return &Null{} }
+func (c *Continue) NotEqu(o Object) Object           { return &Null{} }
+func (c *Continue) Gt(o Object) Object               { return &Null{} }
+func (c *Continue) Gte(o Object) Object              { return &Null{} }
+func (c *Continue) Lt(o Object) Object               { return &Null{} }
+func (c *Continue) Lte(o Object) Object              { return &Null{} }
@@ -0,0 +1,377 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// TagName is the struct tag that names the dict key of a field, as in
+// `interp:"name"`; a "-" tag leaves the field out
+const TagName = "interp"
+
+var objectInterface = reflect.TypeOf((*Object)(nil)).Elem()
+
+// FromGo converts a Go value to an Object. Integers become INT, except
+// int32 which as rune becomes CHAR; slices and arrays become arrays whose
+// elements must all be of the same type; maps with string keys and
+// structs become dicts. Pointers are followed, nil becomes null.
+func FromGo(v any) (Object, error) {
+	return fromValue(reflect.ValueOf(v), "")
+}
+
+func fromValue(v reflect.Value, path string) (Object, error) {
+	if !v.IsValid() {
+		return &Null{}, nil
+	}
+	if v.Type().Implements(objectInterface) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return &Null{}, nil
+		}
+		return v.Interface().(Object), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return &Null{}, nil
+		}
+		return fromValue(v.Elem(), path)
+	case reflect.Bool:
+		return &Boolean{Value: v.Bool()}, nil
+	case reflect.Int32:
+		return &Char{Value: rune(v.Int())}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64:
+		return &Integer{Value: v.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return nil, conversionError(path, "%d overflows %s", v.Uint(), INT_OBJ)
+		}
+		return &Integer{Value: int64(v.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Float{Value: v.Float()}, nil
+	case reflect.String:
+		return &String{Value: v.String()}, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return &Null{}, nil
+		}
+		return fromSlice(v, path)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, conversionError(path, "map key must be string, got %s", v.Type().Key())
+		}
+		if v.IsNil() {
+			return &Null{}, nil
+		}
+		dict := &Dict{Elements: map[string]Object{}}
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			elem, err := fromValue(iter.Value(), joinPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			dict.Elements[key] = elem
+		}
+		return dict, nil
+	case reflect.Struct:
+		dict := &Dict{Elements: map[string]Object{}}
+		for i := 0; i < v.NumField(); i++ {
+			key, ok := fieldKey(v.Type().Field(i))
+			if !ok {
+				continue
+			}
+			elem, err := fromValue(v.Field(i), joinPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			dict.Elements[key] = elem
+		}
+		return dict, nil
+	default:
+		return nil, conversionError(path, "cannot convert %s", v.Type())
+	}
+}
+
+func fromSlice(v reflect.Value, path string) (Object, error) {
+	array := &Array{ArrType: typeOfGo(v.Type().Elem()), Elements: []Object{}}
+	for i := 0; i < v.Len(); i++ {
+		elemPath := fmt.Sprintf("%s[%d]", path, i)
+		elem, err := fromValue(v.Index(i), elemPath)
+		if err != nil {
+			return nil, err
+		}
+		if elem.Type() != NULL_OBJ {
+			if array.ArrType == "" {
+				array.ArrType = elem.Type()
+			}
+			if elem.Type() != array.ArrType {
+				return nil, conversionError(elemPath, "cannot mix %s and %s in array", array.ArrType, elem.Type())
+			}
+		}
+		array.Elements = append(array.Elements, elem)
+	}
+	// no element told the type of the ones to come
+	if array.ArrType == "" {
+		array.ArrType = ANY_OBJ
+	}
+	array.Size = len(array.Elements)
+
+	return array, nil
+}
+
+// typeOfGo returns the object type values of Go type t convert to, or ""
+// when it depends on the value
+func typeOfGo(t reflect.Type) string {
+	if t.Implements(objectInterface) {
+		return ""
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeOfGo(t.Elem())
+	case reflect.Bool:
+		return BOOL_OBJ
+	case reflect.Int32:
+		return CHAR_OBJ
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return INT_OBJ
+	case reflect.Float32, reflect.Float64:
+		return FLOAT_OBJ
+	case reflect.String:
+		return STR_OBJ
+	case reflect.Slice, reflect.Array:
+		return ARRAY_OBJ
+	case reflect.Map, reflect.Struct:
+		return DICT_OBJ
+	default:
+		return ""
+	}
+}
+
+// ToGo stores obj in the value target points to, converting it to the
+// type of that value. A target of type any receives int64, float64, rune,
+// string, bool, []any or map[string]any; objects with no Go counterpart,
+// like functions, can only be stored in any or Object targets.
+func ToGo(obj Object, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, got %T", target)
+	}
+	return toValue(obj, v.Elem(), "")
+}
+
+func toValue(obj Object, v reflect.Value, path string) error {
+	if v.Type() == objectInterface {
+		v.Set(reflect.ValueOf(&obj).Elem())
+		return nil
+	}
+
+	// nulls include the result of calls to void functions
+	if obj.Type() == NULL_OBJ {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() > 0 {
+			break
+		}
+		natural, err := toNatural(obj, path)
+		if err != nil {
+			return err
+		}
+		if natural == nil {
+			v.Set(reflect.Zero(v.Type()))
+		} else {
+			v.Set(reflect.ValueOf(natural))
+		}
+		return nil
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := toValue(obj, elem.Elem(), path); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Bool:
+		if b, ok := obj.(*Boolean); ok {
+			v.SetBool(b.Value)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch obj := obj.(type) {
+		case *Integer:
+			n = obj.Value
+		case *Char:
+			if v.Kind() != reflect.Int32 {
+				return conversionError(path, "cannot convert %s to %s", obj.Type(), v.Type())
+			}
+			n = int64(obj.Value)
+		default:
+			return conversionError(path, "cannot convert %s to %s", obj.Type(), v.Type())
+		}
+		if v.OverflowInt(n) {
+			return conversionError(path, "%d overflows %s", n, v.Type())
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if i, ok := obj.(*Integer); ok {
+			if i.Value < 0 || v.OverflowUint(uint64(i.Value)) {
+				return conversionError(path, "%d overflows %s", i.Value, v.Type())
+			}
+			v.SetUint(uint64(i.Value))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch obj := obj.(type) {
+		case *Float:
+			v.SetFloat(obj.Value)
+			return nil
+		case *Integer:
+			v.SetFloat(float64(obj.Value))
+			return nil
+		}
+	case reflect.String:
+		switch obj := obj.(type) {
+		case *String:
+			v.SetString(obj.Value)
+			return nil
+		case *Char:
+			v.SetString(string(obj.Value))
+			return nil
+		}
+	case reflect.Slice:
+		if arr, ok := obj.(*Array); ok {
+			slice := reflect.MakeSlice(v.Type(), len(arr.Elements), len(arr.Elements))
+			for i, elem := range arr.Elements {
+				if err := toValue(elem, slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			v.Set(slice)
+			return nil
+		}
+	case reflect.Array:
+		if arr, ok := obj.(*Array); ok {
+			if len(arr.Elements) != v.Len() {
+				return conversionError(path, "cannot convert %d elements to %s", len(arr.Elements), v.Type())
+			}
+			for i, elem := range arr.Elements {
+				if err := toValue(elem, v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		if dict, ok := obj.(*Dict); ok {
+			if v.Type().Key().Kind() != reflect.String {
+				return conversionError(path, "map key must be string, got %s", v.Type().Key())
+			}
+			m := reflect.MakeMapWithSize(v.Type(), len(dict.Elements))
+			for key, elem := range dict.Elements {
+				value := reflect.New(v.Type().Elem()).Elem()
+				if err := toValue(elem, value, joinPath(path, key)); err != nil {
+					return err
+				}
+				m.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), value)
+			}
+			v.Set(m)
+			return nil
+		}
+	case reflect.Struct:
+		if dict, ok := obj.(*Dict); ok {
+			for i := 0; i < v.NumField(); i++ {
+				key, ok := fieldKey(v.Type().Field(i))
+				if !ok {
+					continue
+				}
+				elem, ok := dict.Elements[key]
+				if !ok {
+					continue
+				}
+				if err := toValue(elem, v.Field(i), joinPath(path, key)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	return conversionError(path, "cannot convert %s to %s", obj.Type(), v.Type())
+}
+
+// toNatural converts obj to the Go value it is closest to
+func toNatural(obj Object, path string) (any, error) {
+	switch obj := obj.(type) {
+	case *Null:
+		return nil, nil
+	case *Integer:
+		return obj.Value, nil
+	case *Float:
+		return obj.Value, nil
+	case *Char:
+		return obj.Value, nil
+	case *String:
+		return obj.Value, nil
+	case *Boolean:
+		return obj.Value, nil
+	case *Array:
+		elems := make([]any, len(obj.Elements))
+		for i, elem := range obj.Elements {
+			natural, err := toNatural(elem, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = natural
+		}
+		return elems, nil
+	case *Dict:
+		elems := make(map[string]any, len(obj.Elements))
+		for key, elem := range obj.Elements {
+			natural, err := toNatural(elem, joinPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			elems[key] = natural
+		}
+		return elems, nil
+	default:
+		return obj, nil
+	}
+}
+
+// fieldKey returns the dict key of an exported struct field
+func fieldKey(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag := field.Tag.Get(TagName)
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return field.Name, true
+}
+
+func joinPath(path string, key string) string {
+	return fmt.Sprintf("%s[%q]", path, key)
+}
+
+func conversionError(path string, format string, a ...any) error {
+	msg := fmt.Sprintf(format, a...)
+	if path == "" {
+		return fmt.Errorf("%s", msg)
+	}
+	return fmt.Errorf("%s: %s", path, msg)
+}
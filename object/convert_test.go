@@ -0,0 +1,155 @@
+package object
+
+import (
+	"reflect"
+	"testing"
+)
+
+type point struct {
+	X     int
+	Y     int     `interp:"y"`
+	Label string  `interp:"label,omitempty"`
+	Skip  float64 `interp:"-"`
+	note  string
+}
+
+func TestFromGo(t *testing.T) {
+	n := 5
+	tests := []struct {
+		Value    any
+		Expected string
+		Type     string
+	}{
+		{nil, "null", NULL_OBJ},
+		{42, "42", INT_OBJ},
+		{uint8(7), "7", INT_OBJ},
+		{&n, "5", INT_OBJ},
+		{2.5, "2.500000", FLOAT_OBJ},
+		{'é', "é", CHAR_OBJ},
+		{"str", "str", STR_OBJ},
+		{true, "true", BOOL_OBJ},
+		{[]int{1, 2}, "int[2] [1, 2]", ARRAY_OBJ},
+		{[]string{}, "string[0] []", ARRAY_OBJ},
+		{[2]float64{1, 2}, "float[2] [1.000000, 2.000000]", ARRAY_OBJ},
+		{[]any{"a", "b"}, "string[2] [a, b]", ARRAY_OBJ},
+		{[]any{}, "any[0] []", ARRAY_OBJ},
+		{[]any{nil}, "any[1] [null]", ARRAY_OBJ},
+		{[][]int{{1}, {2, 3}}, "array[2] [int[1] [1], int[2] [2, 3]]", ARRAY_OBJ},
+		{map[string]int{"b": 2, "a": 1}, "dict{\"a\": 1, \"b\": 2}", DICT_OBJ},
+		{point{X: 1, Y: 2, Label: "p", Skip: 3}, "dict{\"X\": 1, \"label\": p, \"y\": 2}", DICT_OBJ},
+		{&Integer{Value: 3}, "3", INT_OBJ},
+	}
+
+	for _, tt := range tests {
+		obj, err := FromGo(tt.Value)
+		if err != nil {
+			t.Fatalf("FromGo(%#v): unexpected error: %s", tt.Value, err)
+		}
+		if obj.Inspect() != tt.Expected {
+			t.Errorf("FromGo(%#v): expected %q, got %q", tt.Value, tt.Expected, obj.Inspect())
+		}
+		if obj.Type() != tt.Type {
+			t.Errorf("FromGo(%#v): expected type %s, got %s", tt.Value, tt.Type, obj.Type())
+		}
+	}
+
+	errors := []struct {
+		Value any
+		Error string
+	}{
+		{[]any{1, "a"}, "[1]: cannot mix INT and STRING in array"},
+		{map[int]string{1: "a"}, "map key must be string, got int"},
+		{map[string]any{"f": func() {}}, "[\"f\"]: cannot convert func()"},
+		{uint64(1 << 63), "9223372036854775808 overflows INT"},
+	}
+
+	for _, tt := range errors {
+		_, err := FromGo(tt.Value)
+		if err == nil || err.Error() != tt.Error {
+			t.Errorf("FromGo(%#v): expected error %q, got %v", tt.Value, tt.Error, err)
+		}
+	}
+}
+
+func TestToGo(t *testing.T) {
+	var i int
+	if err := ToGo(&Integer{Value: 3}, &i); err != nil || i != 3 {
+		t.Errorf("expected 3, got %d (%v)", i, err)
+	}
+
+	var f float32
+	if err := ToGo(&Integer{Value: 3}, &f); err != nil || f != 3 {
+		t.Errorf("expected 3, got %f (%v)", f, err)
+	}
+
+	var r rune
+	if err := ToGo(&Char{Value: 'x'}, &r); err != nil || r != 'x' {
+		t.Errorf("expected 'x', got %q (%v)", r, err)
+	}
+
+	var s string
+	if err := ToGo(&Char{Value: 'x'}, &s); err != nil || s != "x" {
+		t.Errorf("expected \"x\", got %q (%v)", s, err)
+	}
+
+	var ints []int
+	arr := &Array{ArrType: INT_OBJ, Size: 2, Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}}
+	if err := ToGo(arr, &ints); err != nil || !reflect.DeepEqual(ints, []int{1, 2}) {
+		t.Errorf("expected [1 2], got %v (%v)", ints, err)
+	}
+
+	var p point
+	dict := &Dict{Elements: map[string]Object{
+		"X":     &Integer{Value: 1},
+		"y":     &Integer{Value: 2},
+		"label": &String{Value: "p"},
+		"other": &Boolean{Value: true},
+	}}
+	if err := ToGo(dict, &p); err != nil || p != (point{X: 1, Y: 2, Label: "p"}) {
+		t.Errorf("expected {1 2 p}, got %+v (%v)", p, err)
+	}
+
+	var m map[string]*int
+	if err := ToGo(&Dict{Elements: map[string]Object{"a": &Integer{Value: 1}, "b": &Null{}}}, &m); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if *m["a"] != 1 || m["b"] != nil {
+		t.Errorf("expected a=1 and b=nil, got %v", m)
+	}
+
+	var v any
+	if err := ToGo(&Dict{Elements: map[string]Object{"a": arr}}, &v); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]any{"a": []any{int64(1), int64(2)}}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %v, got %v", expected, v)
+	}
+
+	var obj Object
+	if err := ToGo(arr, &obj); err != nil || obj != arr {
+		t.Errorf("expected the array itself, got %v (%v)", obj, err)
+	}
+
+	errors := []struct {
+		Obj    Object
+		Target any
+		Error  string
+	}{
+		{&Integer{Value: 1}, i, "target must be a non-nil pointer, got int"},
+		{&Float{Value: 1.5}, &i, "cannot convert FLOAT to int"},
+		{&Integer{Value: 300}, new(int8), "300 overflows int8"},
+		{&Integer{Value: -1}, new(uint), "-1 overflows uint"},
+		{&Char{Value: 'x'}, &i, "cannot convert CHAR to int"},
+		{arr, new([]string), "[0]: cannot convert INT to string"},
+		{arr, new([3]int), "cannot convert 2 elements to [3]int"},
+		{&Dict{Elements: map[string]Object{"y": &String{}}}, &p, "[\"y\"]: cannot convert STRING to int"},
+	}
+
+	for _, tt := range errors {
+		err := ToGo(tt.Obj, tt.Target)
+		if err == nil || err.Error() != tt.Error {
+			t.Errorf("ToGo(%s): expected error %q, got %v", tt.Obj.Inspect(), tt.Error, err)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package object
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -15,13 +16,23 @@ func (d *Dict) Inspect() string {
 	var out bytes.Buffer
 	out.WriteString("dict{")
 	elems := []string{}
-	for k, v := range d.Elements {
-		elems = append(elems, fmt.Sprintf("\"%s\": %s", k, v.Inspect()))
+	for _, k := range d.Keys() {
+		elems = append(elems, fmt.Sprintf("\"%s\": %s", k, d.Elements[k].Inspect()))
 	}
 	out.WriteString(strings.Join(elems, ", "))
 	out.WriteString("}")
 	return out.String()
 }
+
+// Keys returns the keys of the dict in sorted order
+func (d *Dict) Keys() []string {
+	keys := make([]string, 0, len(d.Elements))
+	for k := range d.Elements {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
 func (d *Dict) ToType(objType ObjectType) Object {
 	switch objType {
 	case DictType:
@@ -62,8 +73,15 @@ func (d *Dict) Sub(o Object) Object {
 
 	return &Dict{Elements: elems}
 }
-func (d *Dict) Mul(o Object) Object { return &Null{} }
-func (d *Dict) Div(o Object) Object { return &Null{} }
+func (d *Dict) Mul(o Object) Object    { return &Null{} }
+func (d *Dict) Div(o Object) Object    { return &Null{} }
+func (d *Dict) Mod(o Object) Object    { return &Null{} }
+func (d *Dict) Pow(o Object) Object    { return &Null{} }
+func (d *Dict) BitAnd(o Object) Object { return &Null{} }
+func (d *Dict) BitOr(o Object) Object  { return &Null{} }
+func (d *Dict) BitXor(o Object) Object { return &Null{} }
+func (d *Dict) Shl(o Object) Object    { return &Null{} }
+func (d *Dict) Shr(o Object) Object    { return &Null{} }
 func (d *Dict) Equ(o Object) Object {
 	return &Boolean{Value: d == o.(*Dict)}
 }
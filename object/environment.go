@@ -32,6 +32,18 @@ func (e *Environment) Set(name string, val Object) Object {
 	return val
 }
 
+// Assign updates name in the environment where it was declared
+func (e *Environment) Assign(name string, val Object) (Object, bool) {
+	if _, ok := e.store[name]; ok {
+		e.store[name] = val
+		return val, true
+	}
+	if e.outer != nil {
+		return e.outer.Assign(name, val)
+	}
+	return nil, false
+}
+
 func (e *Environment) Del(name string) {
 	delete(e.store, name)
 }
@@ -1,16 +1,51 @@
 package object
 
+import (
+	"fmt"
+
+	"github.com/menxqk/my-interpreter/token"
+)
+
 type Error struct {
 	Message string
+	Pos     token.Position
+	// Err is the Go error that caused this one, if any
+	Err error
+}
+
+func newArithmeticError(msg string, left Object, operator string, right Object) *Error {
+	return &Error{Message: fmt.Sprintf("%s: %s %s %s", msg, left.Inspect(), operator, right.Inspect())}
+}
+
+func (e *Error) Type() string { return ERROR_OBJ }
+func (e *Error) Inspect() string {
+	if e.Pos.IsValid() {
+		return fmt.Sprintf("ERROR: %s: %s", e.Pos, e.Message)
+	}
+	return "ERROR: " + e.Message
+}
+
+// Error makes runtime errors usable as Go errors
+func (e *Error) Error() string {
+	if e.Pos.IsValid() {
+		return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+	}
+	return e.Message
 }
+func (e *Error) Unwrap() error { return e.Err }
 
-func (e *Error) Type() string                     { return ERROR_OBJ }
-func (e *Error) Inspect() string                  { return "ERROR: " + e.Message }
 func (e *Error) ToType(objType ObjectType) Object { return nil }
 func (e *Error) Add(o Object) Object              { return &Null{} }
 func (e *Error) Sub(o Object) Object              { return &Null{} }
 func (e *Error) Mul(o Object) Object              { return &Null{} }
 func (e *Error) Div(o Object) Object              { return &Null{} }
+func (e *Error) Mod(o Object) Object              { return &Null{} }
+func (e *Error) Pow(o Object) Object              { return &Null{} }
+func (e *Error) BitAnd(o Object) Object           { return &Null{} }
+func (e *Error) BitOr(o Object) Object            { return &Null{} }
+func (e *Error) BitXor(o Object) Object           { return &Null{} }
+func (e *Error) Shl(o Object) Object              { return &Null{} }
+func (e *Error) Shr(o Object) Object              { return &Null{} }
 func (e *Error) Equ(o Object) Object {
 	return &Boolean{Value: e == o.(*Error)}
 }
@@ -33,6 +33,17 @@ func (f *Float) Mul(o Object) Object {
 func (f *Float) Div(o Object) Object {
 	return &Float{Value: f.Value / o.(*Float).Value}
 }
+func (f *Float) Mod(o Object) Object {
+	return &Float{Value: math.Mod(f.Value, o.(*Float).Value)}
+}
+func (f *Float) Pow(o Object) Object {
+	return &Float{Value: math.Pow(f.Value, o.(*Float).Value)}
+}
+func (f *Float) BitAnd(o Object) Object { return &Null{} }
+func (f *Float) BitOr(o Object) Object  { return &Null{} }
+func (f *Float) BitXor(o Object) Object { return &Null{} }
+func (f *Float) Shl(o Object) Object    { return &Null{} }
+func (f *Float) Shr(o Object) Object    { return &Null{} }
 func (f *Float) Equ(o Object) Object {
 	return &Boolean{Value: isFloat64Equal(f.Value, o.(*Float).Value)}
 }
@@ -17,6 +17,11 @@ type Function struct {
 
 func (f *Function) Type() string { return FN_OBJ }
 func (f *Function) Inspect() string {
+	// the zero value of a func variable has no body
+	if f.Body == nil {
+		return "null"
+	}
+
 	var out bytes.Buffer
 	out.WriteString(fmt.Sprintf("%s %s(", f.Identifier.TypeLiteral, f.Identifier.Name))
 	params := []string{}
@@ -33,6 +38,13 @@ func (f *Function) Add(o Object) Object              { return &Null{} }
 func (f *Function) Sub(o Object) Object              { return &Null{} }
 func (f *Function) Mul(o Object) Object              { return &Null{} }
 func (f *Function) Div(o Object) Object              { return &Null{} }
+func (f *Function) Mod(o Object) Object              { return &Null{} }
+func (f *Function) Pow(o Object) Object              { return &Null{} }
+func (f *Function) BitAnd(o Object) Object           { return &Null{} }
+func (f *Function) BitOr(o Object) Object            { return &Null{} }
+func (f *Function) BitXor(o Object) Object           { return &Null{} }
+func (f *Function) Shl(o Object) Object              { return &Null{} }
+func (f *Function) Shr(o Object) Object              { return &Null{} }
 func (f *Function) Equ(o Object) Object {
 	return &Boolean{Value: f == o.(*Function)}
 }
@@ -1,6 +1,9 @@
 package object
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 type Integer struct {
 	Value int64
@@ -28,7 +31,60 @@ func (i *Integer) Mul(o Object) Object {
 	return &Integer{Value: i.Value * o.(*Integer).Value}
 }
 func (i *Integer) Div(o Object) Object {
-	return &Integer{Value: i.Value / o.(*Integer).Value}
+	oInt := o.(*Integer)
+	if oInt.Value == 0 {
+		return newArithmeticError("division by zero", i, "/", oInt)
+	}
+	if i.Value == math.MinInt64 && oInt.Value == -1 {
+		return newArithmeticError("integer overflow", i, "/", oInt)
+	}
+	return &Integer{Value: i.Value / oInt.Value}
+}
+func (i *Integer) Mod(o Object) Object {
+	oInt := o.(*Integer)
+	if oInt.Value == 0 {
+		return newArithmeticError("division by zero", i, "%", oInt)
+	}
+	return &Integer{Value: i.Value % oInt.Value}
+}
+func (i *Integer) Pow(o Object) Object {
+	exp := o.(*Integer).Value
+	if exp < 0 {
+		return newArithmeticError("negative exponent", i, "**", o)
+	}
+
+	// exponentiation by squaring
+	result, base := int64(1), i.Value
+	for exp > 0 {
+		if exp&1 == 1 {
+			result *= base
+		}
+		base *= base
+		exp >>= 1
+	}
+
+	return &Integer{Value: result}
+}
+func (i *Integer) BitAnd(o Object) Object {
+	return &Integer{Value: i.Value & o.(*Integer).Value}
+}
+func (i *Integer) BitOr(o Object) Object {
+	return &Integer{Value: i.Value | o.(*Integer).Value}
+}
+func (i *Integer) BitXor(o Object) Object {
+	return &Integer{Value: i.Value ^ o.(*Integer).Value}
+}
+func (i *Integer) Shl(o Object) Object {
+	if o.(*Integer).Value < 0 {
+		return newArithmeticError("negative shift count", i, "<<", o)
+	}
+	return &Integer{Value: i.Value << o.(*Integer).Value}
+}
+func (i *Integer) Shr(o Object) Object {
+	if o.(*Integer).Value < 0 {
+		return newArithmeticError("negative shift count", i, ">>", o)
+	}
+	return &Integer{Value: i.Value >> o.(*Integer).Value}
 }
 func (i *Integer) Equ(o Object) Object {
 	return &Boolean{Value: i.Value == o.(*Integer).Value}
@@ -0,0 +1,136 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrMemoryLimit     = errors.New("memory limit exceeded")
+	ErrStringLimit     = errors.New("string length limit exceeded")
+	ErrCollectionLimit = errors.New("collection size limit exceeded")
+)
+
+// approximate sizes, in bytes, of the parts of an object
+const (
+	headerSize = 16
+	elemSize   = 16
+)
+
+// Memory accounts for the approximate number of bytes allocated by the
+// strings, arrays and dicts created during an evaluation and enforces
+// limits on them. A zero limit means no limit; a nil *Memory accounts
+// for nothing.
+type Memory struct {
+	MaxBytes          int64
+	MaxStringLen      int
+	MaxCollectionSize int
+
+	used int64
+}
+
+// Used returns the number of bytes accounted since the last Reset
+func (m *Memory) Used() int64 {
+	if m == nil {
+		return 0
+	}
+	return m.used
+}
+
+func (m *Memory) Reset() {
+	if m != nil {
+		m.used = 0
+	}
+}
+
+// Alloc accounts for a newly created object
+func (m *Memory) Alloc(obj Object) *Error {
+	if m == nil {
+		return nil
+	}
+
+	switch obj := obj.(type) {
+	case *String:
+		if errObj := m.checkString(len(obj.Value)); errObj != nil {
+			return errObj
+		}
+	case *Array:
+		if errObj := m.CheckCollection(len(obj.Elements)); errObj != nil {
+			return errObj
+		}
+	case *Dict:
+		if errObj := m.CheckCollection(len(obj.Elements)); errObj != nil {
+			return errObj
+		}
+	}
+
+	return m.account(SizeOf(obj))
+}
+
+// Grow accounts for n elements added to an existing array or dict
+func (m *Memory) Grow(obj Object, n int) *Error {
+	if m == nil {
+		return nil
+	}
+
+	switch obj := obj.(type) {
+	case *Array:
+		if errObj := m.CheckCollection(len(obj.Elements)); errObj != nil {
+			return errObj
+		}
+	case *Dict:
+		if errObj := m.CheckCollection(len(obj.Elements)); errObj != nil {
+			return errObj
+		}
+	}
+
+	return m.account(int64(n) * elemSize)
+}
+
+// CheckCollection reports an error if an array or dict of n elements
+// exceeds the collection size limit, so it can be checked before the
+// elements are allocated
+func (m *Memory) CheckCollection(n int) *Error {
+	if m == nil || m.MaxCollectionSize <= 0 || n <= m.MaxCollectionSize {
+		return nil
+	}
+	return limitError(ErrCollectionLimit, n, int64(m.MaxCollectionSize))
+}
+
+func (m *Memory) checkString(n int) *Error {
+	if m.MaxStringLen <= 0 || n <= m.MaxStringLen {
+		return nil
+	}
+	return limitError(ErrStringLimit, n, int64(m.MaxStringLen))
+}
+
+func (m *Memory) account(size int64) *Error {
+	m.used += size
+	if m.MaxBytes > 0 && m.used > m.MaxBytes {
+		return limitError(ErrMemoryLimit, int(m.used), m.MaxBytes)
+	}
+	return nil
+}
+
+func limitError(err error, value int, limit int64) *Error {
+	return &Error{Message: fmt.Sprintf("%s: %d > %d", err, value, limit), Err: err}
+}
+
+// SizeOf returns the approximate size in bytes of obj, not counting the
+// objects it holds. Scalars are not counted as they never grow.
+func SizeOf(obj Object) int64 {
+	switch obj := obj.(type) {
+	case *String:
+		return headerSize + int64(len(obj.Value))
+	case *Array:
+		return headerSize + int64(len(obj.Elements))*elemSize
+	case *Dict:
+		size := int64(headerSize)
+		for key := range obj.Elements {
+			size += elemSize + int64(len(key))
+		}
+		return size
+	default:
+		return 0
+	}
+}
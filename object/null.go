@@ -9,9 +9,22 @@ func (n *Null) Add(o Object) Object              { return n }
 func (n *Null) Sub(o Object) Object              { return n }
 func (n *Null) Mul(o Object) Object              { return n }
 func (n *Null) Div(o Object) Object              { return n }
+func (n *Null) Mod(o Object) Object              { return n }
+func (n *Null) Pow(o Object) Object              { return n }
+func (n *Null) BitAnd(o Object) Object           { return n }
+func (n *Null) BitOr(o Object) Object            { return n }
+func (n *Null) BitXor(o Object) Object           { return n }
+func (n *Null) Shl(o Object) Object              { return n }
+func (n *Null) Shr(o Object) Object              { return n }
 func (n *Null) Equ(o Object) Object              { return n }
 func (n *Null) NotEqu(o Object) Object           { return n }
 func (n *Null) Gt(o Object) Object               { return n }
 func (n *Null) Gte(o Object) Object              { return n }
 func (n *Null) Lt(o Object) Object               { return n }
 func (n *Null) Lte(o Object) Object              { return n }
+
+// Void is the result of a call to a void function, a null that cannot
+// be used as a value
+type Void struct {
+	Null
+}
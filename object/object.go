@@ -25,8 +25,15 @@ const (
 	BOOL_OBJ  = "BOOLEAN"
 	DICT_OBJ  = "DICT"
 
-	RET_VAL_OBJ = "RETURN_VALUE"
-	FN_OBJ      = "FUNCTION"
+	// element type of empty arrays not declared with one, they take the
+	// type of the first element pushed
+	ANY_OBJ = "ANY"
+
+	RET_VAL_OBJ  = "RETURN_VALUE"
+	BREAK_OBJ    = "BREAK"
+	CONTINUE_OBJ = "CONTINUE"
+	FN_OBJ       = "FUNCTION"
+	BUILTIN_OBJ  = "BUILTIN"
 )
 
 type Object interface {
@@ -37,6 +44,14 @@ type Object interface {
 	Sub(Object) Object
 	Mul(Object) Object
 	Div(Object) Object
+	Mod(Object) Object
+	Pow(Object) Object
+
+	BitAnd(Object) Object
+	BitOr(Object) Object
+	BitXor(Object) Object
+	Shl(Object) Object
+	Shr(Object) Object
 
 	Equ(Object) Object
 	NotEqu(Object) Object
@@ -63,6 +78,8 @@ func GetZeroValueObject(objType string) Object {
 		return &Boolean{}
 	case DICT_OBJ:
 		return &Dict{Elements: map[string]Object{}}
+	case FN_OBJ:
+		return &Function{}
 	default:
 		return &Null{}
 	}
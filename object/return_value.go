@@ -11,6 +11,13 @@ func (rv *ReturnValue) Add(o Object) Object              { return &Null{} }
 func (rv *ReturnValue) Sub(o Object) Object              { return &Null{} }
 func (rv *ReturnValue) Mul(o Object) Object              { return &Null{} }
 func (rv *ReturnValue) Div(o Object) Object              { return &Null{} }
+func (rv *ReturnValue) Mod(o Object) Object              { return &Null{} }
+func (rv *ReturnValue) Pow(o Object) Object              { return &Null{} }
+func (rv *ReturnValue) BitAnd(o Object) Object           { return &Null{} }
+func (rv *ReturnValue) BitOr(o Object) Object            { return &Null{} }
+func (rv *ReturnValue) BitXor(o Object) Object           { return &Null{} }
+func (rv *ReturnValue) Shl(o Object) Object              { return &Null{} }
+func (rv *ReturnValue) Shr(o Object) Object              { return &Null{} }
 func (rv *ReturnValue) Equ(o Object) Object              { return &Null{} }
 func (rv *ReturnValue) NotEqu(o Object) Object           { return &Null{} }
 func (rv *ReturnValue) Gt(o Object) Object               { return &Null{} }
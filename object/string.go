@@ -22,9 +22,16 @@ func (s *String) ToType(objType ObjectType) Object {
 func (s *String) Add(o Object) Object {
 	return &String{Value: s.Value + o.(*String).Value}
 }
-func (s *String) Sub(o Object) Object { return &Null{} }
-func (s *String) Mul(o Object) Object { return &Null{} }
-func (s *String) Div(o Object) Object { return &Null{} }
+func (s *String) Sub(o Object) Object    { return &Null{} }
+func (s *String) Mul(o Object) Object    { return &Null{} }
+func (s *String) Div(o Object) Object    { return &Null{} }
+func (s *String) Mod(o Object) Object    { return &Null{} }
+func (s *String) Pow(o Object) Object    { return &Null{} }
+func (s *String) BitAnd(o Object) Object { return &Null{} }
+func (s *String) BitOr(o Object) Object  { return &Null{} }
+func (s *String) BitXor(o Object) Object { return &Null{} }
+func (s *String) Shl(o Object) Object    { return &Null{} }
+func (s *String) Shr(o Object) Object    { return &Null{} }
 func (s *String) Equ(o Object) Object {
 	return &Boolean{Value: s.Value == o.(*String).Value}
 }
@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/menxqk/my-interpreter/token"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "unknown"
+	}
+}
+
+// Diagnostic codes
+const (
+	CodeIllegalToken       = "illegal-token"
+	CodeUnexpectedToken    = "unexpected-token"
+	CodeExpectedExpression = "expected-expression"
+	CodeMissingSemicolon   = "missing-semicolon"
+	CodeInvalidLiteral     = "invalid-literal"
+	CodeInvalidAssignment  = "invalid-assignment"
+	CodeMisplacedStatement = "misplaced-statement"
+	CodeInvalidReturn      = "invalid-return"
+)
+
+var hints = map[string]string{
+	CodeMissingSemicolon:   "statements end with ';'",
+	CodeInvalidAssignment:  "only variables and elements of arrays and dicts can be assigned",
+	CodeMisplacedStatement: "break and continue can only be used inside while and for loops",
+	CodeInvalidReturn:      "void functions end with 'return;' or without a return statement",
+}
+
+// Diagnostic describes a problem found while parsing, Start and End
+// delimit the source it refers to, End being exclusive
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Message  string
+	Start    token.Position
+	End      token.Position
+	Token    token.Token
+	Hint     string
+}
+
+func newDiagnostic(severity Severity, code string, tok token.Token, msg string) Diagnostic {
+	return Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Message:  msg,
+		Start:    tok.Pos,
+		End:      tokenEnd(tok),
+		Token:    tok,
+		Hint:     hints[code],
+	}
+}
+
+// String returns the diagnostic as "line:col: message"
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Start, d.Message)
+}
+
+// Render writes the diagnostic followed by the line of src it refers to,
+// with the offending source underlined by carets. name, if not empty,
+// prefixes the position.
+func (d Diagnostic) Render(w io.Writer, name string, src string) {
+	prefix := ""
+	if name != "" {
+		prefix = name + ":"
+	}
+	fmt.Fprintf(w, "%s%s: %s: %s [%s]\n", prefix, d.Start, d.Severity, d.Message, d.Code)
+
+	line, ok := sourceLine(src, d.Start.Line)
+	if ok {
+		gutter := fmt.Sprintf(" %d | ", d.Start.Line)
+		fmt.Fprintf(w, "%s%s\n", gutter, line)
+		fmt.Fprintf(w, "%s| %s%s\n", strings.Repeat(" ", len(gutter)-2), caretIndent(line, d.Start.Column), carets(d))
+	}
+
+	if d.Hint != "" {
+		fmt.Fprintf(w, "hint: %s\n", d.Hint)
+	}
+}
+
+// sourceLine returns line n of src, starting at 1
+func sourceLine(src string, n int) (string, bool) {
+	lines := strings.Split(src, "\n")
+	if n < 1 || n > len(lines) {
+		return "", false
+	}
+	return strings.TrimSuffix(lines[n-1], "\r"), true
+}
+
+// caretIndent returns the blanks that put a caret under column col of
+// line, keeping tabs so they line up the same way
+func caretIndent(line string, col int) string {
+	var out strings.Builder
+	for i, r := range []rune(line) {
+		if i >= col-1 {
+			break
+		}
+		if r == '\t' {
+			out.WriteRune('\t')
+		} else {
+			out.WriteRune(' ')
+		}
+	}
+	return out.String()
+}
+
+func carets(d Diagnostic) string {
+	n := 1
+	if d.End.Line == d.Start.Line && d.End.Column > d.Start.Column {
+		n = d.End.Column - d.Start.Column
+	}
+	return strings.Repeat("^", n)
+}
+
+// tokenEnd returns the position right after tok in the source
+func tokenEnd(tok token.Token) token.Position {
+	if !tok.End.IsValid() {
+		return tok.Pos
+	}
+	return tok.End
+}
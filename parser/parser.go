@@ -11,28 +11,44 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	LOGICAL_OR
+	LOGICAL_AND
+	BIT_OR
+	BIT_XOR
+	BIT_AND
 	EQUALS
 	LESSGREATER
+	SHIFT
 	SUM
 	PRODUCT
+	POWER
 	PREFIX
 	CALL
 	ELEM
 )
 
 var precedences = map[string]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.LTE:      LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.GTE:      LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.LPAREN:   CALL,
-	token.LBRACKET: ELEM,
+	token.OR:        LOGICAL_OR,
+	token.AND:       LOGICAL_AND,
+	token.PIPE:      BIT_OR,
+	token.CARET:     BIT_XOR,
+	token.AMPERSAND: BIT_AND,
+	token.EQ:        EQUALS,
+	token.NOT_EQ:    EQUALS,
+	token.LT:        LESSGREATER,
+	token.LTE:       LESSGREATER,
+	token.GT:        LESSGREATER,
+	token.GTE:       LESSGREATER,
+	token.LSHIFT:    SHIFT,
+	token.RSHIFT:    SHIFT,
+	token.PLUS:      SUM,
+	token.MINUS:     SUM,
+	token.SLASH:     PRODUCT,
+	token.ASTERISK:  PRODUCT,
+	token.PERCENT:   PRODUCT,
+	token.POWER:     POWER,
+	token.LPAREN:    CALL,
+	token.LBRACKET:  ELEM,
 }
 
 type PrefixParseFn func() ast.Expression
@@ -43,7 +59,7 @@ type Parser struct {
 
 	debug bool
 
-	errors []string
+	diagnostics []Diagnostic
 
 	curToken  token.Token
 	nextToken token.Token
@@ -51,6 +67,15 @@ type Parser struct {
 
 	prefixParseFns map[string]PrefixParseFn
 	infixParseFns  map[string]InfixParseFn
+
+	// number of enclosing loops, for break and continue
+	loopDepth int
+
+	// return type of the enclosing function, for return statements
+	returnType string
+
+	// open '(', '[' and '{' up to the current token
+	nesting []string
 }
 
 func New(l *lexer.Lexer, debug ...bool) *Parser {
@@ -60,7 +85,7 @@ func New(l *lexer.Lexer, debug ...bool) *Parser {
 		d = debug[0]
 	}
 
-	p := &Parser{l: l, errors: make([]string, 0), debug: d}
+	p := &Parser{l: l, diagnostics: []Diagnostic{}, debug: d}
 	p.advanceToken()
 	p.advanceToken()
 
@@ -72,6 +97,7 @@ func New(l *lexer.Lexer, debug ...bool) *Parser {
 	p.registerPrefixParseFn(token.STRING_VALUE, p.parseStringLiteral)
 	p.registerPrefixParseFn(token.BANG, p.parsePrefixExpression)
 	p.registerPrefixParseFn(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefixParseFn(token.TILDE, p.parsePrefixExpression)
 	p.registerPrefixParseFn(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefixParseFn(token.IF, p.parseIfExpression)
 	p.registerPrefixParseFn(token.TRUE, p.parseBoolean)
@@ -79,20 +105,37 @@ func New(l *lexer.Lexer, debug ...bool) *Parser {
 	p.registerPrefixParseFn(token.NULL, p.parseNull)
 	p.registerPrefixParseFn(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefixParseFn(token.LBRACE, p.parseDictLiteral)
+	p.registerPrefixParseFn(token.INT_TYPE, p.parseTypeExpression)
+	p.registerPrefixParseFn(token.FLOAT_TYPE, p.parseTypeExpression)
+	p.registerPrefixParseFn(token.CHAR_TYPE, p.parseTypeExpression)
+	p.registerPrefixParseFn(token.STRING_TYPE, p.parseTypeExpression)
+	p.registerPrefixParseFn(token.DICT_TYPE, p.parseTypeExpression)
+	p.registerPrefixParseFn(token.BOOL_TYPE, p.parseTypeExpression)
+	p.registerPrefixParseFn(token.FUNC_TYPE, p.parseTypeExpression)
+	p.registerPrefixParseFn(token.VOID_TYPE, p.parseTypeExpression)
 
 	p.infixParseFns = make(map[string]InfixParseFn)
 	p.registerInfixParseFn(token.PLUS, p.parseInfixExpression)
 	p.registerInfixParseFn(token.MINUS, p.parseInfixExpression)
 	p.registerInfixParseFn(token.ASTERISK, p.parseInfixExpression)
 	p.registerInfixParseFn(token.SLASH, p.parseInfixExpression)
+	p.registerInfixParseFn(token.PERCENT, p.parseInfixExpression)
+	p.registerInfixParseFn(token.POWER, p.parseInfixExpression)
+	p.registerInfixParseFn(token.AMPERSAND, p.parseInfixExpression)
+	p.registerInfixParseFn(token.PIPE, p.parseInfixExpression)
+	p.registerInfixParseFn(token.CARET, p.parseInfixExpression)
+	p.registerInfixParseFn(token.LSHIFT, p.parseInfixExpression)
+	p.registerInfixParseFn(token.RSHIFT, p.parseInfixExpression)
 	p.registerInfixParseFn(token.EQ, p.parseInfixExpression)
 	p.registerInfixParseFn(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfixParseFn(token.LT, p.parseInfixExpression)
 	p.registerInfixParseFn(token.LTE, p.parseInfixExpression)
 	p.registerInfixParseFn(token.GT, p.parseInfixExpression)
 	p.registerInfixParseFn(token.GTE, p.parseInfixExpression)
+	p.registerInfixParseFn(token.AND, p.parseInfixExpression)
+	p.registerInfixParseFn(token.OR, p.parseInfixExpression)
 	p.registerInfixParseFn(token.LPAREN, p.parseCallExpression)
-	p.registerInfixParseFn(token.LBRACKET, p.parseCollectionElementExpression)
+	p.registerInfixParseFn(token.LBRACKET, p.parseIndexExpression)
 
 	return p
 }
@@ -102,6 +145,7 @@ func (p *Parser) ParseProgram() *ast.Program {
 	program.Statements = []ast.Statement{}
 
 	for !p.curTokenIs(token.EOF) {
+		start, depth, errs := p.curToken.Pos, p.statementNesting(), len(p.diagnostics)
 		stmt := p.parseStatement()
 		if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
@@ -110,6 +154,8 @@ func (p *Parser) ParseProgram() *ast.Program {
 			if p.debug {
 				fmt.Printf("statement: %s\n", stmt.DebugString())
 			}
+		} else if len(p.diagnostics) > errs {
+			p.synchronize(depth, start)
 		}
 
 		p.advanceToken()
@@ -119,15 +165,41 @@ func (p *Parser) ParseProgram() *ast.Program {
 }
 
 func (p *Parser) HasErrors() bool {
-	return len(p.errors) > 0
+	for _, d := range p.diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
 }
 
+// Errors returns the errors found while parsing as "line:col: message"
 func (p *Parser) Errors() []string {
-	return p.errors
+	errors := []string{}
+	for _, d := range p.diagnostics {
+		if d.Severity == SeverityError {
+			errors = append(errors, d.String())
+		}
+	}
+	return errors
+}
+
+// Diagnostics returns all the problems found while parsing
+func (p *Parser) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
+// appendError reports an error about tok, the offending token
+func (p *Parser) appendError(code string, tok token.Token, msg string) {
+	p.diagnostics = append(p.diagnostics, newDiagnostic(SeverityError, code, tok, msg))
 }
 
-func (p *Parser) appendError(msg string) {
-	p.errors = append(p.errors, msg)
+// appendRangeError records an error about the source from start to the
+// end of tok, the last token of the offending expression
+func (p *Parser) appendRangeError(code string, start token.Position, tok token.Token, msg string) {
+	d := newDiagnostic(SeverityError, code, tok, msg)
+	d.Start = start
+	p.diagnostics = append(p.diagnostics, d)
 }
 
 func (p *Parser) advanceToken() {
@@ -136,6 +208,89 @@ func (p *Parser) advanceToken() {
 	if !p.nextTokenIs(token.EOF) {
 		p.nextToken = p.l.NextToken()
 	}
+
+	switch p.curToken.Type {
+	case token.LPAREN, token.LBRACKET, token.LBRACE:
+		p.nesting = append(p.nesting, p.curToken.Type)
+	case token.RPAREN, token.RBRACKET, token.RBRACE:
+		// a closer also closes the unfinished ones it encloses
+		for i := len(p.nesting) - 1; i >= 0; i-- {
+			if closers[p.nesting[i]] == p.curToken.Type {
+				p.nesting = p.nesting[:i]
+				break
+			}
+		}
+	}
+}
+
+var closers = map[string]string{
+	token.LPAREN:   token.RPAREN,
+	token.LBRACKET: token.RBRACKET,
+	token.LBRACE:   token.RBRACE,
+}
+
+// statementNesting returns the nesting depth of the statement that
+// starts at the current token
+func (p *Parser) statementNesting() int {
+	switch p.curToken.Type {
+	case token.LPAREN, token.LBRACKET, token.LBRACE:
+		return len(p.nesting) - 1
+	}
+	return len(p.nesting)
+}
+
+// synchronize skips the rest of the statement at start with nesting depth
+// that failed to parse, up to its ending ';' or '}', the '}' closing the
+// enclosing block, or a line not indented past start
+func (p *Parser) synchronize(depth int, start token.Position) {
+	// a statement only missing its ';' at the end of a line is complete
+	if d := p.diagnostics[len(p.diagnostics)-1]; d.Code == CodeMissingSemicolon &&
+		len(p.nesting) == depth && p.nextToken.Pos.Line > p.curToken.Pos.Line {
+		return
+	}
+
+	for !p.curTokenIs(token.EOF) && !p.nextTokenIs(token.EOF) {
+		if len(p.nesting) < depth {
+			return
+		}
+
+		if len(p.nesting) == depth {
+			if p.curTokenIs(token.SEMICOLON) || p.nextTokenIs(token.RBRACE) {
+				return
+			}
+			if p.curTokenIs(token.RBRACE) && !continuesBlock[p.nextToken.Type] {
+				return
+			}
+		}
+
+		if p.nextToken.Pos.Line > p.curToken.Pos.Line && p.nextToken.Pos.Column <= start.Column &&
+			p.unclosedParens(depth) {
+			p.nesting = p.nesting[:depth]
+			return
+		}
+
+		p.advanceToken()
+	}
+}
+
+// continuesBlock are the tokens that continue a statement after one of
+// its blocks: the else of an if, and the ';' or the call arguments after
+// a function literal
+var continuesBlock = map[string]bool{
+	token.ELSE:      true,
+	token.SEMICOLON: true,
+	token.LPAREN:    true,
+}
+
+// unclosedParens reports whether the tokens opened past depth are
+// only '(' and '['
+func (p *Parser) unclosedParens(depth int) bool {
+	for _, t := range p.nesting[depth:] {
+		if t == token.LBRACE {
+			return false
+		}
+	}
+	return true
 }
 
 func (p *Parser) curTokenIs(tokenType string) bool {
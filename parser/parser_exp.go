@@ -2,7 +2,6 @@ package parser
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/menxqk/my-interpreter/ast"
 	"github.com/menxqk/my-interpreter/token"
@@ -11,18 +10,21 @@ import (
 func (p *Parser) parseExpression(precedence int) ast.Expression {
 	if p.curTokenIs(token.ILLEGAL) {
 		msg := fmt.Sprintf("illegal token: %s", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeIllegalToken, p.curToken, msg)
 		return nil
 	}
 
 	prefixFn := p.prefixParseFns[p.curToken.Type]
 	if prefixFn == nil {
 		msg := fmt.Sprintf("no prefix parse function for: %s", p.curToken.Type)
-		p.appendError(msg)
+		p.appendError(CodeExpectedExpression, p.curToken, msg)
 		return nil
 	}
 
 	leftExp := prefixFn()
+	if leftExp == nil {
+		return nil
+	}
 
 	for !p.nextTokenIs(token.SEMICOLON) && precedence < p.nextPrecedence() {
 		infix := p.infixParseFns[p.nextToken.Type]
@@ -32,20 +34,72 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		p.advanceToken() // next operator
 
 		leftExp = infix(leftExp)
+		if leftExp == nil {
+			return nil
+		}
 	}
 
 	return leftExp
 }
 
 func (p *Parser) parseIdentifier() ast.Expression {
-	exp := &ast.Identifier{}
+	exp := &ast.Identifier{Pos: p.curToken.Pos}
 	exp.Name = p.curToken.Literal
 
 	return exp
 }
 
+// parseTypeExpression parses an expression starting with a type keyword:
+// a function literal, as in int (int a) { ... }, or a call to the
+// conversion builtin named after the type, as in int(x)
+func (p *Parser) parseTypeExpression() ast.Expression {
+	typeTok := p.curToken
+	if !p.nextTokenIs(token.LPAREN) {
+		msg := fmt.Sprintf("unexpected type %s", typeTok.Literal)
+		p.appendError(CodeExpectedExpression, typeTok, msg)
+		return nil
+	}
+	p.advanceToken() // '('
+	p.advanceToken() // parameters or arguments
+
+	// only int, float and char name conversions, a parameter list is
+	// either empty and followed by the body or starts with a type and a name
+	isParams := p.curTokenIs(token.RPAREN) && p.nextTokenIs(token.LBRACE) ||
+		token.IsDataType(p.curToken.Literal) && p.nextTokenIs(token.IDENT)
+	if isParams || !conversionTypes[typeTok.Type] {
+		fn := &ast.FunctionExpression{
+			Pos: typeTok.Pos,
+			Identifier: ast.Identifier{
+				Pos:         typeTok.Pos,
+				Type:        typeTok.Type,
+				TypeLiteral: typeTok.Literal,
+			},
+		}
+		return p.parseFunction(fn)
+	}
+
+	exp := &ast.CallExpression{
+		Pos:      typeTok.Pos,
+		Function: &ast.Identifier{Pos: typeTok.Pos, Name: typeTok.Literal},
+	}
+	exp.Arguments = p.parseCallArguments()
+	if exp.Arguments == nil {
+		return nil
+	}
+
+	return exp
+}
+
+// conversionTypes are the types whose keyword is also the name of a
+// conversion builtin
+var conversionTypes = map[string]bool{
+	token.INT_TYPE:   true,
+	token.FLOAT_TYPE: true,
+	token.CHAR_TYPE:  true,
+}
+
 func (p *Parser) parsePrefixExpression() ast.Expression {
-	exp := &ast.PrefixExpression{}
+	exp := &ast.PrefixExpression{Pos: p.curToken.Pos}
 	exp.Operator = p.curToken.Literal
 
 	p.advanceToken() // expression
@@ -59,7 +113,7 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 }
 
 func (p *Parser) parseGroupedExpression() ast.Expression {
-	exp := &ast.GroupedExpression{}
+	exp := &ast.GroupedExpression{Pos: p.curToken.Pos}
 
 	p.advanceToken() // expression
 
@@ -70,7 +124,7 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 
 	if !p.nextTokenIs(token.RPAREN) {
 		msg := fmt.Sprintf("missing ')' after: %s", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeUnexpectedToken, p.curToken, msg)
 		return nil
 	}
 	p.advanceToken() // ')'
@@ -79,12 +133,16 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 }
 
 func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
-	exp := &ast.InfixExpression{}
+	exp := &ast.InfixExpression{Pos: p.curToken.Pos}
 
 	exp.Left = left
 	exp.Operator = p.curToken.Literal
 
 	precedence := p.curPrecedence()
+	// '**' is right associative
+	if p.curTokenIs(token.POWER) {
+		precedence--
+	}
 	p.advanceToken() // right expression
 	exp.Right = p.parseExpression(precedence)
 	if exp.Right == nil {
@@ -95,11 +153,11 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 }
 
 func (p *Parser) parseIfExpression() ast.Expression {
-	exp := &ast.IfExpression{}
+	exp := &ast.IfExpression{Pos: p.curToken.Pos}
 
 	if !p.nextTokenIs(token.LPAREN) {
 		msg := fmt.Sprintf("expected '(' got= %s", p.nextToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
 		return nil
 	}
 	p.advanceToken() // '('
@@ -112,14 +170,14 @@ func (p *Parser) parseIfExpression() ast.Expression {
 
 	if !p.nextTokenIs(token.RPAREN) {
 		msg := fmt.Sprintf("expected ')' got= %s", p.nextToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
 		return nil
 	}
 	p.advanceToken() // ')'
 
 	if !p.nextTokenIs(token.LBRACE) {
 		msg := fmt.Sprintf("expected '{' got= %s", p.nextToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
 		return nil
 	}
 	p.advanceToken() // '{'
@@ -133,7 +191,7 @@ func (p *Parser) parseIfExpression() ast.Expression {
 		p.advanceToken() // else
 		if !p.nextTokenIs(token.LBRACE) {
 			msg := fmt.Sprintf("expected '{' got= %s", p.nextToken.Literal)
-			p.appendError(msg)
+			p.appendError(CodeUnexpectedToken, p.nextToken, msg)
 			return nil
 		}
 		p.advanceToken() // '{'
@@ -148,12 +206,20 @@ func (p *Parser) parseIfExpression() ast.Expression {
 }
 
 func (p *Parser) parseCallExpression(left ast.Expression) ast.Expression {
-	exp := &ast.CallExpression{}
-	exp.Identifier = ast.Identifier{
-		Name: left.Literal(),
-	}
+	exp := &ast.CallExpression{Pos: left.Position(), Function: left}
 
 	p.advanceToken() // call arguments
+	exp.Arguments = p.parseCallArguments()
+	if exp.Arguments == nil {
+		return nil
+	}
+
+	return exp
+}
+
+// parseCallArguments parses the arguments of a call, starting at the
+// token after '(', up to its ')'
+func (p *Parser) parseCallArguments() []ast.Expression {
 	args := []ast.Expression{}
 	for !p.curTokenIs(token.RPAREN) && !p.curTokenIs(token.EOF) {
 		exp := p.parseExpression(LOWEST)
@@ -168,96 +234,56 @@ func (p *Parser) parseCallExpression(left ast.Expression) ast.Expression {
 
 		p.advanceToken()
 	}
-	exp.Arguments = args
-
-	return exp
-}
 
-func (p *Parser) parseCollectionElementExpression(left ast.Expression) ast.Expression {
-	switch p.nextToken.Type {
-	case token.INT_VALUE:
-		return p.parseArrayElementExpression(left)
-	case token.STRING_VALUE:
-		return p.parseDictElementExpression(left)
-	default:
-		return nil
-	}
+	return args
 }
 
-func (p *Parser) parseArrayElementExpression(left ast.Expression) ast.Expression {
-	exp := &ast.ArrayElementExpression{}
-	exp.Identifier = ast.Identifier{
-		Name: left.Literal(),
-	}
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	var index ast.Expression
+	if !p.nextTokenIs(token.COLON) {
+		p.advanceToken() // index expression
 
-	p.advanceToken() // array element index
-	if !p.curTokenIs(token.INT_VALUE) {
-		msg := fmt.Sprintf("expected integer value for array element index, got %s", p.curToken.Literal)
-		p.appendError(msg)
-		return nil
+		index = p.parseExpression(LOWEST)
+		if index == nil {
+			return nil
+		}
 	}
 
-	val, err := strconv.ParseInt(p.curToken.Literal, 10, 64)
-	if err != nil {
-		msg := fmt.Sprintf("could not parse integer for array element index: %s", p.curToken.Literal)
-		p.appendError(msg)
-		return nil
+	if p.nextTokenIs(token.COLON) {
+		p.advanceToken() // ':'
+		return p.parseSliceExpression(left, index)
 	}
-	exp.Index = int(val)
 
 	if !p.nextTokenIs(token.RBRACKET) {
 		msg := fmt.Sprintf("expected ']' after %s", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeUnexpectedToken, p.curToken, msg)
 		return nil
 	}
 	p.advanceToken() // ']'
 
-	if p.nextTokenIs(token.ASSIGN) {
-		p.advanceToken() // '='
-		p.advanceToken() // expression
-
-		elemExp := p.parseExpression(LOWEST)
-		if exp == nil {
-			return nil
-		}
-		exp.Expression = elemExp
-	}
-
-	return exp
+	return &ast.IndexExpression{Pos: left.Position(), Left: left, Index: index}
 }
 
-func (p *Parser) parseDictElementExpression(left ast.Expression) ast.Expression {
-	exp := &ast.DictElementExpression{}
-	exp.Identifier = ast.Identifier{
-		Name: left.Literal(),
-	}
+// parseSliceExpression parses the rest of a slice of left, starting at
+// the ':' that follows its (possibly omitted) start bound
+func (p *Parser) parseSliceExpression(left ast.Expression, start ast.Expression) ast.Expression {
+	exp := &ast.SliceExpression{Pos: left.Position(), Left: left, Start: start}
 
-	p.advanceToken() // dict key
-	if !p.curTokenIs(token.STRING_VALUE) {
-		msg := fmt.Sprintf("expected string for dict key, got %s", p.curToken.Literal)
-		p.appendError(msg)
-		return nil
-	}
+	if !p.nextTokenIs(token.RBRACKET) {
+		p.advanceToken() // end expression
 
-	exp.Key = p.curToken.Literal
+		exp.End = p.parseExpression(LOWEST)
+		if exp.End == nil {
+			return nil
+		}
+	}
 
 	if !p.nextTokenIs(token.RBRACKET) {
 		msg := fmt.Sprintf("expected ']' after %s", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeUnexpectedToken, p.curToken, msg)
 		return nil
 	}
 	p.advanceToken() // ']'
 
-	if p.nextTokenIs(token.ASSIGN) {
-		p.advanceToken() // '='
-		p.advanceToken() // expression
-
-		elemExp := p.parseExpression(LOWEST)
-		if exp == nil {
-			return nil
-		}
-		exp.Expression = elemExp
-	}
-
 	return exp
 }
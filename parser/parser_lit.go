@@ -9,12 +9,12 @@ import (
 )
 
 func (p *Parser) parseIntegerLiteral() ast.Expression {
-	lit := &ast.IntegerLiteral{}
+	lit := &ast.IntegerLiteral{Pos: p.curToken.Pos}
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
 	if err != nil {
 		msg := fmt.Sprintf("could not convert %s to integer", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeInvalidLiteral, p.curToken, msg)
 		return nil
 	}
 
@@ -24,12 +24,12 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 }
 
 func (p *Parser) parseFloatLiteral() ast.Expression {
-	lit := &ast.FloatLiteral{}
+	lit := &ast.FloatLiteral{Pos: p.curToken.Pos}
 
 	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
 	if err != nil {
 		msg := fmt.Sprintf("could not convert %s to float", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeInvalidLiteral, p.curToken, msg)
 		return nil
 	}
 
@@ -39,13 +39,13 @@ func (p *Parser) parseFloatLiteral() ast.Expression {
 }
 
 func (p *Parser) parseCharLiteral() ast.Expression {
-	lit := &ast.CharLiteral{}
+	lit := &ast.CharLiteral{Pos: p.curToken.Pos}
 
 	if len(p.curToken.Literal) > 0 {
 		lit.Value = rune(p.curToken.Literal[0])
 	} else {
 		msg := fmt.Sprintf("could not convert %s to char", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeInvalidLiteral, p.curToken, msg)
 		return nil
 	}
 
@@ -53,7 +53,7 @@ func (p *Parser) parseCharLiteral() ast.Expression {
 }
 
 func (p *Parser) parseStringLiteral() ast.Expression {
-	lit := &ast.StringLiteral{}
+	lit := &ast.StringLiteral{Pos: p.curToken.Pos}
 
 	lit.Value = p.curToken.Literal
 
@@ -63,20 +63,20 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 func (p *Parser) parseBoolean() ast.Expression {
 	switch p.curToken.Literal {
 	case "true":
-		return &ast.BooleanLiteral{Value: true}
+		return &ast.BooleanLiteral{Pos: p.curToken.Pos, Value: true}
 	case "false":
-		return &ast.BooleanLiteral{Value: false}
+		return &ast.BooleanLiteral{Pos: p.curToken.Pos, Value: false}
 	default:
 		return nil
 	}
 }
 
 func (p *Parser) parseNull() ast.Expression {
-	return &ast.NullLiteral{}
+	return &ast.NullLiteral{Pos: p.curToken.Pos}
 }
 
 func (p *Parser) parseArrayLiteral() ast.Expression {
-	lit := &ast.ArrayLiteral{}
+	lit := &ast.ArrayLiteral{Pos: p.curToken.Pos}
 
 	p.advanceToken() // expressions
 
@@ -99,7 +99,7 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 }
 
 func (p *Parser) parseDictLiteral() ast.Expression {
-	lit := &ast.DictLiteral{}
+	lit := &ast.DictLiteral{Pos: p.curToken.Pos}
 
 	p.advanceToken() // expressions
 
@@ -107,7 +107,7 @@ func (p *Parser) parseDictLiteral() ast.Expression {
 	for !p.curTokenIs(token.EOF) && !p.curTokenIs(token.RBRACE) {
 		if !p.curTokenIs(token.STRING_VALUE) {
 			msg := fmt.Sprintf("expected %s, got %s", token.STRING_VALUE, p.curToken.Type)
-			p.appendError(msg)
+			p.appendError(CodeUnexpectedToken, p.curToken, msg)
 			return nil
 		}
 
@@ -116,7 +116,7 @@ func (p *Parser) parseDictLiteral() ast.Expression {
 		p.advanceToken() // ':'
 		if !p.curTokenIs(token.COLON) {
 			msg := fmt.Sprintf("expected %s, got %s", token.COLON, p.curToken.Literal)
-			p.appendError(msg)
+			p.appendError(CodeUnexpectedToken, p.curToken, msg)
 			return nil
 		}
 
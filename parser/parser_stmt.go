@@ -10,16 +10,21 @@ import (
 
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
-	case token.INT_TYPE, token.FLOAT_TYPE, token.CHAR_TYPE, token.STRING_TYPE, token.DICT_TYPE:
-		return p.parseDeclarationStatement()
-	case token.IDENT:
-		if p.nextTokenIs(token.ASSIGN) {
-			return p.parseAssignmentStatement()
-		} else {
+	case token.INT_TYPE, token.FLOAT_TYPE, token.CHAR_TYPE, token.STRING_TYPE, token.DICT_TYPE, token.BOOL_TYPE,
+		token.FUNC_TYPE, token.VOID_TYPE:
+		// a conversion or a function literal
+		if p.nextTokenIs(token.LPAREN) {
 			return p.parseExpressionStatement()
 		}
+		return p.parseDeclarationStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.WHILE:
+		return p.parseWhileStatement()
+	case token.FOR:
+		return p.parseForStatement()
+	case token.BREAK, token.CONTINUE:
+		return p.parseLoopControlStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -29,10 +34,22 @@ func (p *Parser) parseDeclarationStatement() ast.Statement {
 	p.advanceToken() // ident
 	if !p.curTokenIs(token.IDENT) {
 		msg := fmt.Sprintf("expected identifier after: %s; got=%s", p.prevToken.Literal, p.curToken.Type)
-		p.appendError(msg)
+		p.appendError(CodeUnexpectedToken, p.curToken, msg)
+		return nil
+	}
+
+	if p.prevTokenIs(token.VOID_TYPE) && !p.nextTokenIs(token.LPAREN) {
+		msg := fmt.Sprintf("expected '(' after void %s, only functions can be void", p.curToken.Literal)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
 		return nil
 	}
 
+	return p.parseDeclaration()
+}
+
+// parseDeclaration parses the declaration whose type and name are
+// the previous and current tokens
+func (p *Parser) parseDeclaration() ast.Statement {
 	if p.nextTokenIs(token.LPAREN) {
 		return p.parseFunctionDeclarationStatement()
 	} else if p.nextTokenIs(token.LBRACKET) {
@@ -43,10 +60,12 @@ func (p *Parser) parseDeclarationStatement() ast.Statement {
 }
 
 func (p *Parser) parseFunctionDeclarationStatement() ast.Statement {
-	stmt := &ast.FunctionDeclarationStatement{}
+	stmt := &ast.FunctionDeclarationStatement{Pos: p.prevToken.Pos}
 
 	funcExp := &ast.FunctionExpression{
+		Pos: p.prevToken.Pos,
 		Identifier: ast.Identifier{
+			Pos:         p.curToken.Pos,
 			Name:        p.curToken.Literal,
 			Type:        p.prevToken.Type,
 			TypeLiteral: p.prevToken.Literal,
@@ -56,21 +75,33 @@ func (p *Parser) parseFunctionDeclarationStatement() ast.Statement {
 	p.advanceToken() // '('
 	p.advanceToken() // params
 
+	if p.parseFunction(funcExp) == nil {
+		return nil
+	}
+	stmt.Function = funcExp
+
+	return stmt
+}
+
+// parseFunction parses the parameters and the body of fn, starting at
+// the token after '('
+func (p *Parser) parseFunction(fn *ast.FunctionExpression) ast.Expression {
 	params := []*ast.Identifier{}
 	for !p.curTokenIs(token.RPAREN) && !p.curTokenIs(token.EOF) {
 		if !token.IsDataType(p.curToken.Literal) {
 			msg := fmt.Sprintf("expected data type, got= %s[%s]", p.curToken.Literal, p.curToken.Type)
-			p.appendError(msg)
+			p.appendError(CodeUnexpectedToken, p.curToken, msg)
 			return nil
 		}
 		param := &ast.Identifier{
+			Pos:         p.curToken.Pos,
 			Type:        p.curToken.Type,
 			TypeLiteral: p.curToken.Literal,
 		}
 
 		if !p.nextTokenIs(token.IDENT) {
 			msg := fmt.Sprintf("expected IDENT after: %s, got= %s", p.curToken.Literal, p.nextToken.Type)
-			p.appendError(msg)
+			p.appendError(CodeUnexpectedToken, p.nextToken, msg)
 			return nil
 		}
 		p.advanceToken() // IDENT
@@ -84,28 +115,34 @@ func (p *Parser) parseFunctionDeclarationStatement() ast.Statement {
 
 		p.advanceToken()
 	}
-	funcExp.Parameters = params
+	fn.Parameters = params
 
 	p.advanceToken() // '{'
 	if !p.curTokenIs(token.LBRACE) {
-		msg := fmt.Sprintf("expected '{' in function '%s' declaration, got=%s", funcExp.Identifier.Name, p.curToken.Literal)
-		p.appendError(msg)
+		msg := fmt.Sprintf("expected '{' in function '%s' declaration, got=%s", fn.Identifier.Name, p.curToken.Literal)
+		if fn.Identifier.Name == "" {
+			msg = fmt.Sprintf("expected '{' in function literal, got=%s", p.curToken.Literal)
+		}
+		p.appendError(CodeUnexpectedToken, p.curToken, msg)
 		return nil
 	}
 
-	funcExp.Body = p.parseBlockStatement()
-	if funcExp.Body == nil {
+	// break and continue cannot cross a function boundary
+	loopDepth, returnType := p.loopDepth, p.returnType
+	p.loopDepth, p.returnType = 0, fn.Identifier.Type
+	fn.Body = p.parseBlockStatement()
+	p.loopDepth, p.returnType = loopDepth, returnType
+	if fn.Body == nil {
 		return nil
 	}
 
-	stmt.Function = funcExp
-
-	return stmt
+	return fn
 }
 
 func (p *Parser) parseArrayDeclarationStatement() ast.Statement {
-	stmt := &ast.ArrayDeclarationStatement{}
+	stmt := &ast.ArrayDeclarationStatement{Pos: p.prevToken.Pos}
 	stmt.Identifier = ast.Identifier{
+		Pos:         p.curToken.Pos,
 		Name:        p.curToken.Literal,
 		Type:        p.prevToken.Type,
 		TypeLiteral: p.prevToken.Literal,
@@ -119,7 +156,7 @@ func (p *Parser) parseArrayDeclarationStatement() ast.Statement {
 		intVal, err := strconv.ParseInt(p.curToken.Literal, 10, 64)
 		if err != nil {
 			msg := fmt.Sprintf("expected integer for array size, got %s", p.curToken.Literal)
-			p.appendError(msg)
+			p.appendError(CodeUnexpectedToken, p.curToken, msg)
 			return nil
 		}
 
@@ -128,10 +165,23 @@ func (p *Parser) parseArrayDeclarationStatement() ast.Statement {
 
 	if !p.nextTokenIs(token.RBRACKET) {
 		msg := fmt.Sprintf("expected ']', got %s", p.nextToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
 		return nil
 	}
 	p.advanceToken() // ']'
+	stmt.Dimensions = 1
+
+	// further dimensions: '[' ']'
+	for p.nextTokenIs(token.LBRACKET) {
+		p.advanceToken() // '['
+		if !p.nextTokenIs(token.RBRACKET) {
+			msg := fmt.Sprintf("expected ']', got %s", p.nextToken.Literal)
+			p.appendError(CodeUnexpectedToken, p.nextToken, msg)
+			return nil
+		}
+		p.advanceToken() // ']'
+		stmt.Dimensions++
+	}
 
 	if p.nextTokenIs(token.SEMICOLON) { // did not initialize array
 		p.advanceToken() // ';'
@@ -140,7 +190,7 @@ func (p *Parser) parseArrayDeclarationStatement() ast.Statement {
 
 	if !p.nextTokenIs(token.ASSIGN) || p.nextTokenIs(token.SEMICOLON) { // array initialization?
 		msg := fmt.Sprintf("expected '=' or ';', got %s", p.nextToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
 		return nil
 	}
 
@@ -154,7 +204,7 @@ func (p *Parser) parseArrayDeclarationStatement() ast.Statement {
 
 	if !p.nextTokenIs(token.SEMICOLON) {
 		msg := fmt.Sprintf("missing ';' after %s", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeMissingSemicolon, p.curToken, msg)
 		return nil
 	}
 	p.advanceToken() // ';'
@@ -163,8 +213,9 @@ func (p *Parser) parseArrayDeclarationStatement() ast.Statement {
 }
 
 func (p *Parser) parseVariableDeclarationStatement() ast.Statement {
-	stmt := &ast.VariableDeclarationStatement{}
+	stmt := &ast.VariableDeclarationStatement{Pos: p.prevToken.Pos}
 	stmt.Identifier = ast.Identifier{
+		Pos:         p.curToken.Pos,
 		Name:        p.curToken.Literal,
 		Type:        p.prevToken.Type,
 		TypeLiteral: p.prevToken.Literal,
@@ -185,7 +236,7 @@ func (p *Parser) parseVariableDeclarationStatement() ast.Statement {
 
 	if !p.nextTokenIs(token.SEMICOLON) {
 		msg := fmt.Sprintf("missing ';' after %s", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeMissingSemicolon, p.curToken, msg)
 		return nil
 	}
 	p.advanceToken() // ';'
@@ -193,23 +244,26 @@ func (p *Parser) parseVariableDeclarationStatement() ast.Statement {
 	return stmt
 }
 
-func (p *Parser) parseAssignmentStatement() ast.Statement {
-	stmt := &ast.AssignmentStatement{}
-	stmt.Identifier = ast.Identifier{
-		Name: p.curToken.Literal,
-	}
-
-	p.advanceToken() // '='
-	p.advanceToken() // expression
+var assignmentOperators = map[string]bool{
+	token.ASSIGN:          true,
+	token.PLUS_ASSIGN:     true,
+	token.MINUS_ASSIGN:    true,
+	token.ASTERISK_ASSIGN: true,
+	token.SLASH_ASSIGN:    true,
+	token.PERCENT_ASSIGN:  true,
+	token.INCREMENT:       true,
+	token.DECREMENT:       true,
+}
 
-	stmt.Expression = p.parseExpression(LOWEST)
-	if stmt.Expression == nil {
+func (p *Parser) parseAssignmentStatement(left ast.Expression) ast.Statement {
+	stmt := p.parseAssignment(left)
+	if stmt == nil {
 		return nil
 	}
 
 	if !p.nextTokenIs(token.SEMICOLON) {
 		msg := fmt.Sprintf("missing ';' after %s", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeMissingSemicolon, p.curToken, msg)
 		return nil
 	}
 	p.advanceToken() // ';'
@@ -217,23 +271,58 @@ func (p *Parser) parseAssignmentStatement() ast.Statement {
 	return stmt
 }
 
+// parseAssignment parses an assignment to left, with the assignment
+// operator as next token, up to, but not including, its terminator
+func (p *Parser) parseAssignment(left ast.Expression) *ast.AssignmentStatement {
+	switch left.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+	default:
+		msg := fmt.Sprintf("cannot assign to %s", left.String())
+		p.appendRangeError(CodeInvalidAssignment, left.Position(), p.curToken, msg)
+		return nil
+	}
+
+	p.advanceToken() // operator
+	stmt := &ast.AssignmentStatement{
+		Pos:      left.Position(),
+		Left:     left,
+		Operator: p.curToken.Literal,
+	}
+
+	if p.curTokenIs(token.INCREMENT) || p.curTokenIs(token.DECREMENT) {
+		return stmt
+	}
+
+	p.advanceToken() // expression
+
+	stmt.Expression = p.parseExpression(LOWEST)
+	if stmt.Expression == nil {
+		return nil
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseExpressionStatement() ast.Statement {
-	stmt := &ast.ExpressionStatement{}
+	stmt := &ast.ExpressionStatement{Pos: p.curToken.Pos}
 
 	stmt.Expression = p.parseExpression(LOWEST)
 	if stmt.Expression == nil {
 		return nil
 	}
 
+	if assignmentOperators[p.nextToken.Type] {
+		return p.parseAssignmentStatement(stmt.Expression)
+	}
+
 	_, isIfExp := stmt.Expression.(*ast.IfExpression)
-	_, isFuncExp := stmt.Expression.(*ast.FunctionExpression)
-	if !p.nextTokenIs(token.SEMICOLON) && !isIfExp && !isFuncExp {
+	if !p.nextTokenIs(token.SEMICOLON) && !isIfExp {
 		msg := fmt.Sprintf("missing ';' after %s", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeMissingSemicolon, p.curToken, msg)
 		return nil
 	}
 
-	if !isIfExp && !isFuncExp {
+	if !isIfExp {
 		p.advanceToken() // ';'
 	}
 
@@ -241,18 +330,30 @@ func (p *Parser) parseExpressionStatement() ast.Statement {
 }
 
 func (p *Parser) parseReturnStatement() ast.Statement {
-	stmt := &ast.ReturnStatement{}
+	stmt := &ast.ReturnStatement{Pos: p.curToken.Pos}
+
+	// a return without a value
+	if p.nextTokenIs(token.SEMICOLON) {
+		p.advanceToken() // ';'
+		return stmt
+	}
 
 	p.advanceToken() // expression
+	valueTok := p.curToken
 
 	stmt.ReturnValue = p.parseExpression(LOWEST)
 	if stmt.ReturnValue == nil {
 		return nil
 	}
 
+	if p.returnType == token.VOID_TYPE {
+		p.appendError(CodeInvalidReturn, valueTok, "void function cannot return a value")
+		return nil
+	}
+
 	if !p.nextTokenIs(token.SEMICOLON) {
 		msg := fmt.Sprintf("missing ';' after %s", p.curToken.Literal)
-		p.appendError(msg)
+		p.appendError(CodeMissingSemicolon, p.curToken, msg)
 		return nil
 	}
 	p.advanceToken() // ';'
@@ -261,19 +362,260 @@ func (p *Parser) parseReturnStatement() ast.Statement {
 }
 
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
-	block := &ast.BlockStatement{}
+	block := &ast.BlockStatement{Pos: p.curToken.Pos}
 	block.Statements = []ast.Statement{}
 
 	p.advanceToken() // after '{'
 
+	// a failed statement is skipped to report the errors of the next ones
+	failed := false
 	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
+		start, depth, errs := p.curToken.Pos, p.statementNesting(), len(p.diagnostics)
 		stmt := p.parseStatement()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		} else if len(p.diagnostics) > errs {
+			failed = true
+			p.synchronize(depth, start)
+			// the failed statement consumed the '}' closing the block
+			if len(p.nesting) < depth {
+				break
+			}
+		}
 		p.advanceToken()
-		if stmt == nil {
+	}
+
+	if failed {
+		return nil
+	}
+	return block
+}
+
+func (p *Parser) parseWhileStatement() ast.Statement {
+	stmt := &ast.WhileStatement{Pos: p.curToken.Pos}
+
+	if !p.nextTokenIs(token.LPAREN) {
+		msg := fmt.Sprintf("expected '(' got= %s", p.nextToken.Literal)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
+		return nil
+	}
+	p.advanceToken() // '('
+	p.advanceToken() // expression
+
+	stmt.Condition = p.parseExpression(LOWEST)
+	if stmt.Condition == nil {
+		return nil
+	}
+
+	if !p.nextTokenIs(token.RPAREN) {
+		msg := fmt.Sprintf("expected ')' got= %s", p.nextToken.Literal)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
+		return nil
+	}
+	p.advanceToken() // ')'
+
+	stmt.Body = p.parseLoopBody()
+	if stmt.Body == nil {
+		return nil
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseForStatement() ast.Statement {
+	stmt := &ast.ForStatement{Pos: p.curToken.Pos}
+
+	if !p.nextTokenIs(token.LPAREN) {
+		msg := fmt.Sprintf("expected '(' got= %s", p.nextToken.Literal)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
+		return nil
+	}
+	p.advanceToken() // '('
+	p.advanceToken() // init statement
+
+	if !p.curTokenIs(token.SEMICOLON) {
+		switch p.curToken.Type {
+		case token.INT_TYPE, token.FLOAT_TYPE, token.CHAR_TYPE, token.STRING_TYPE, token.DICT_TYPE, token.BOOL_TYPE, token.FUNC_TYPE:
+			p.advanceToken() // ident
+			if !p.curTokenIs(token.IDENT) {
+				msg := fmt.Sprintf("expected identifier after: %s; got=%s", p.prevToken.Literal, p.curToken.Type)
+				p.appendError(CodeUnexpectedToken, p.curToken, msg)
+				return nil
+			}
+			if p.nextTokenIs(token.IN) || p.nextTokenIs(token.COMMA) {
+				return p.parseForInStatement(stmt.Pos)
+			}
+			stmt.Init = p.parseDeclaration()
+		case token.IDENT:
+			stmt.Init = p.parseStatement()
+		default:
+			msg := fmt.Sprintf("expected declaration or assignment in for loop, got= %s", p.curToken.Literal)
+			p.appendError(CodeUnexpectedToken, p.curToken, msg)
+			return nil
+		}
+		if stmt.Init == nil {
 			return nil
 		}
-		block.Statements = append(block.Statements, stmt)
 	}
+	p.advanceToken() // condition
 
-	return block
+	if !p.curTokenIs(token.SEMICOLON) {
+		stmt.Condition = p.parseExpression(LOWEST)
+		if stmt.Condition == nil {
+			return nil
+		}
+
+		if !p.nextTokenIs(token.SEMICOLON) {
+			msg := fmt.Sprintf("missing ';' after %s", p.curToken.Literal)
+			p.appendError(CodeMissingSemicolon, p.curToken, msg)
+			return nil
+		}
+		p.advanceToken() // ';'
+	}
+	p.advanceToken() // post statement
+
+	if !p.curTokenIs(token.RPAREN) {
+		stmt.Post = p.parseSimpleStatement()
+		if stmt.Post == nil {
+			return nil
+		}
+
+		if !p.nextTokenIs(token.RPAREN) {
+			msg := fmt.Sprintf("expected ')' got= %s", p.nextToken.Literal)
+			p.appendError(CodeUnexpectedToken, p.nextToken, msg)
+			return nil
+		}
+		p.advanceToken() // ')'
+	}
+
+	stmt.Body = p.parseLoopBody()
+	if stmt.Body == nil {
+		return nil
+	}
+
+	return stmt
+}
+
+// parseForInStatement parses the rest of a range loop, starting at the
+// name of its first loop variable
+func (p *Parser) parseForInStatement(pos token.Position) ast.Statement {
+	stmt := &ast.ForInStatement{Pos: pos}
+
+	stmt.Value = &ast.Identifier{
+		Pos:         p.curToken.Pos,
+		Name:        p.curToken.Literal,
+		Type:        p.prevToken.Type,
+		TypeLiteral: p.prevToken.Literal,
+	}
+
+	if p.nextTokenIs(token.COMMA) {
+		p.advanceToken() // ','
+		p.advanceToken() // data type
+		if !token.IsDataType(p.curToken.Literal) {
+			msg := fmt.Sprintf("expected data type, got= %s[%s]", p.curToken.Literal, p.curToken.Type)
+			p.appendError(CodeUnexpectedToken, p.curToken, msg)
+			return nil
+		}
+
+		if !p.nextTokenIs(token.IDENT) {
+			msg := fmt.Sprintf("expected IDENT after: %s, got= %s", p.curToken.Literal, p.nextToken.Type)
+			p.appendError(CodeUnexpectedToken, p.nextToken, msg)
+			return nil
+		}
+		p.advanceToken() // IDENT
+
+		stmt.Key = stmt.Value
+		stmt.Value = &ast.Identifier{
+			Pos:         p.curToken.Pos,
+			Name:        p.curToken.Literal,
+			Type:        p.prevToken.Type,
+			TypeLiteral: p.prevToken.Literal,
+		}
+	}
+
+	if !p.nextTokenIs(token.IN) {
+		msg := fmt.Sprintf("expected 'in' got= %s", p.nextToken.Literal)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
+		return nil
+	}
+	p.advanceToken() // 'in'
+	p.advanceToken() // expression
+
+	stmt.Iterable = p.parseExpression(LOWEST)
+	if stmt.Iterable == nil {
+		return nil
+	}
+
+	if !p.nextTokenIs(token.RPAREN) {
+		msg := fmt.Sprintf("expected ')' got= %s", p.nextToken.Literal)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
+		return nil
+	}
+	p.advanceToken() // ')'
+
+	stmt.Body = p.parseLoopBody()
+	if stmt.Body == nil {
+		return nil
+	}
+
+	return stmt
+}
+
+// parseSimpleStatement parses an assignment or an expression that is
+// not terminated by ';', as in the post statement of a for loop
+func (p *Parser) parseSimpleStatement() ast.Statement {
+	stmt := &ast.ExpressionStatement{Pos: p.curToken.Pos}
+	stmt.Expression = p.parseExpression(LOWEST)
+	if stmt.Expression == nil {
+		return nil
+	}
+
+	if assignmentOperators[p.nextToken.Type] {
+		assignStmt := p.parseAssignment(stmt.Expression)
+		if assignStmt == nil {
+			return nil
+		}
+		return assignStmt
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseLoopBody() *ast.BlockStatement {
+	if !p.nextTokenIs(token.LBRACE) {
+		msg := fmt.Sprintf("expected '{' got= %s", p.nextToken.Literal)
+		p.appendError(CodeUnexpectedToken, p.nextToken, msg)
+		return nil
+	}
+	p.advanceToken() // '{'
+
+	p.loopDepth++
+	body := p.parseBlockStatement()
+	p.loopDepth--
+
+	return body
+}
+
+func (p *Parser) parseLoopControlStatement() ast.Statement {
+	var stmt ast.Statement
+	if p.curTokenIs(token.BREAK) {
+		stmt = &ast.BreakStatement{Pos: p.curToken.Pos}
+	} else {
+		stmt = &ast.ContinueStatement{Pos: p.curToken.Pos}
+	}
+
+	if p.loopDepth == 0 {
+		msg := fmt.Sprintf("%s outside of loop", p.curToken.Literal)
+		p.appendError(CodeMisplacedStatement, p.curToken, msg)
+		return nil
+	}
+
+	if !p.nextTokenIs(token.SEMICOLON) {
+		msg := fmt.Sprintf("missing ';' after %s", p.curToken.Literal)
+		p.appendError(CodeMissingSemicolon, p.curToken, msg)
+		return nil
+	}
+	p.advanceToken() // ';'
+
+	return stmt
 }
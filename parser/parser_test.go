@@ -42,7 +42,8 @@ func TestParseProgram(t *testing.T) {
 			}},
 		},
 		{"x = 20;", &ast.AssignmentStatement{
-			Identifier: ast.Identifier{Name: "x"},
+			Left:       &ast.Identifier{Name: "x"},
+			Operator:   "=",
 			Expression: &ast.IntegerLiteral{Value: 20}},
 		},
 		{"1 + 1;", &ast.ExpressionStatement{
@@ -54,7 +55,7 @@ func TestParseProgram(t *testing.T) {
 		},
 		{"concat(\"string1\",\"string2\");", &ast.ExpressionStatement{
 			Expression: &ast.CallExpression{
-				Identifier: ast.Identifier{Name: "concat"},
+				Function: &ast.Identifier{Name: "concat"},
 				Arguments: []ast.Expression{
 					&ast.StringLiteral{Value: "string1"},
 					&ast.StringLiteral{Value: "string2"},
@@ -74,10 +75,10 @@ func TestParseProgram(t *testing.T) {
 
 	if p.HasErrors() {
 		var out bytes.Buffer
-		for i, e := range p.errors {
+		for i, e := range p.Errors() {
 			out.WriteString(fmt.Sprintf("%d - %s\n", i, e))
 		}
-		t.Fatalf("expected zero errors, got=%d\n%s", len(p.errors), out.String())
+		t.Fatalf("expected zero errors, got=%d\n%s", len(p.Errors()), out.String())
 	}
 
 	if len(program.Statements) != len(tests) {
@@ -109,8 +110,8 @@ func TestParseProgram(t *testing.T) {
 	p = New(l)
 	program = p.ParseProgram()
 
-	if len(p.errors) != len(tests) {
-		t.Fatalf("expected %d error, got=%d", len(tests), len(p.errors))
+	if len(p.Errors()) != len(tests) {
+		t.Fatalf("expected %d error, got=%d", len(tests), len(p.Errors()))
 	}
 
 	if len(program.Statements) != 0 {
@@ -163,23 +164,28 @@ func TestParseStatement(t *testing.T) {
 			}},
 		},
 		{"x = 20;", &ast.AssignmentStatement{
-			Identifier: ast.Identifier{Name: "x"},
+			Left:       &ast.Identifier{Name: "x"},
+			Operator:   "=",
 			Expression: &ast.IntegerLiteral{Value: 20}},
 		},
 		{"y = 35.60;", &ast.AssignmentStatement{
-			Identifier: ast.Identifier{Name: "y"},
+			Left:       &ast.Identifier{Name: "y"},
+			Operator:   "=",
 			Expression: &ast.FloatLiteral{Value: 35.60}},
 		},
 		{"s = \"string\";", &ast.AssignmentStatement{
-			Identifier: ast.Identifier{Name: "s"},
+			Left:       &ast.Identifier{Name: "s"},
+			Operator:   "=",
 			Expression: &ast.StringLiteral{Value: "string"}},
 		},
 		{"c = 'c';", &ast.AssignmentStatement{
-			Identifier: ast.Identifier{Name: "c"},
+			Left:       &ast.Identifier{Name: "c"},
+			Operator:   "=",
 			Expression: &ast.CharLiteral{Value: 'c'}},
 		},
 		{"d = 1 + 3;", &ast.AssignmentStatement{
-			Identifier: ast.Identifier{Name: "d"},
+			Left:     &ast.Identifier{Name: "d"},
+			Operator: "=",
 			Expression: &ast.InfixExpression{
 				Left:     &ast.IntegerLiteral{Value: 1},
 				Operator: "+",
@@ -215,7 +221,8 @@ func TestParseStatement(t *testing.T) {
 				Consequence: &ast.BlockStatement{
 					Statements: []ast.Statement{
 						&ast.AssignmentStatement{
-							Identifier: ast.Identifier{Name: "x"},
+							Left:       &ast.Identifier{Name: "x"},
+							Operator:   "=",
 							Expression: &ast.IntegerLiteral{Value: 2},
 						},
 					},
@@ -223,7 +230,8 @@ func TestParseStatement(t *testing.T) {
 				Alternative: &ast.BlockStatement{
 					Statements: []ast.Statement{
 						&ast.AssignmentStatement{
-							Identifier: ast.Identifier{Name: "y"},
+							Left:       &ast.Identifier{Name: "y"},
+							Operator:   "=",
 							Expression: &ast.FloatLiteral{Value: 3.60},
 						},
 					},
@@ -232,7 +240,7 @@ func TestParseStatement(t *testing.T) {
 		},
 		{"add(a, 1 + 1);", &ast.ExpressionStatement{
 			Expression: &ast.CallExpression{
-				Identifier: ast.Identifier{Name: "add"},
+				Function: &ast.Identifier{Name: "add"},
 				Arguments: []ast.Expression{
 					&ast.Identifier{Name: "a"},
 					&ast.InfixExpression{
@@ -272,17 +280,32 @@ func TestParseStatement(t *testing.T) {
 			}},
 		},
 		{"arr[1];", &ast.ExpressionStatement{
-			Expression: &ast.ArrayElementExpression{
-				Identifier: ast.Identifier{Name: "arr"},
-				Index:      1,
-				Expression: nil,
+			Expression: &ast.IndexExpression{
+				Left:  &ast.Identifier{Name: "arr"},
+				Index: &ast.IntegerLiteral{Value: 1},
 			}},
 		},
-		{"arr[0] = 10;", &ast.ExpressionStatement{
-			Expression: &ast.ArrayElementExpression{
-				Identifier: ast.Identifier{Name: "arr"},
-				Index:      0,
-				Expression: &ast.IntegerLiteral{Value: 10},
+		{"arr[0] = 10;", &ast.AssignmentStatement{
+			Left: &ast.IndexExpression{
+				Left:  &ast.Identifier{Name: "arr"},
+				Index: &ast.IntegerLiteral{Value: 0},
+			},
+			Operator:   "=",
+			Expression: &ast.IntegerLiteral{Value: 10}},
+		},
+		{"arr[i + 1] = arr[i];", &ast.AssignmentStatement{
+			Left: &ast.IndexExpression{
+				Left: &ast.Identifier{Name: "arr"},
+				Index: &ast.InfixExpression{
+					Left:     &ast.Identifier{Name: "i"},
+					Operator: "+",
+					Right:    &ast.IntegerLiteral{Value: 1},
+				},
+			},
+			Operator: "=",
+			Expression: &ast.IndexExpression{
+				Left:  &ast.Identifier{Name: "arr"},
+				Index: &ast.Identifier{Name: "i"},
 			}},
 		},
 		{"dict d = {\"one\": 1, \"two\": 2};", &ast.VariableDeclarationStatement{
@@ -298,29 +321,89 @@ func TestParseStatement(t *testing.T) {
 				},
 			}},
 		},
-		{"d[\"one\"];", &ast.ExpressionStatement{
-			Expression: &ast.DictElementExpression{
-				Identifier: ast.Identifier{Name: "d"},
-				Key:        "one",
-				Expression: nil,
+		{"bool done = false;", &ast.VariableDeclarationStatement{
+			Identifier: ast.Identifier{Name: "done", Type: token.BOOL_TYPE, TypeLiteral: "bool"},
+			Expression: &ast.BooleanLiteral{Value: false}},
+		},
+		{"bool flags[2] = [true, false];", &ast.ArrayDeclarationStatement{
+			Identifier: ast.Identifier{Name: "flags", Type: token.BOOL_TYPE, TypeLiteral: "bool"},
+			Size:       2,
+			Expression: &ast.ArrayLiteral{
+				Elements: []ast.Expression{
+					&ast.BooleanLiteral{Value: true},
+					&ast.BooleanLiteral{Value: false},
+				},
 			}},
 		},
-		{"d[\"one\"] = 20;", &ast.ExpressionStatement{
-			Expression: &ast.DictElementExpression{
-				Identifier: ast.Identifier{Name: "d"},
-				Key:        "one",
-				Expression: &ast.IntegerLiteral{Value: 20},
+		{"bool not(bool b) { return !b; }", &ast.FunctionDeclarationStatement{
+			Function: &ast.FunctionExpression{
+				Identifier: ast.Identifier{Name: "not", Type: token.BOOL_TYPE, TypeLiteral: "bool"},
+				Parameters: []*ast.Identifier{
+					{Name: "b", Type: token.BOOL_TYPE, TypeLiteral: "bool"},
+				},
+				Body: &ast.BlockStatement{
+					Statements: []ast.Statement{
+						&ast.ReturnStatement{
+							ReturnValue: &ast.PrefixExpression{
+								Operator:   "!",
+								Expression: &ast.Identifier{Name: "b"},
+							},
+						},
+					},
+				},
 			}},
 		},
+		{"d[\"one\"];", &ast.ExpressionStatement{
+			Expression: &ast.IndexExpression{
+				Left:  &ast.Identifier{Name: "d"},
+				Index: &ast.StringLiteral{Value: "one"},
+			}},
+		},
+		{"d[\"one\"] = 20;", &ast.AssignmentStatement{
+			Left: &ast.IndexExpression{
+				Left:  &ast.Identifier{Name: "d"},
+				Index: &ast.StringLiteral{Value: "one"},
+			},
+			Operator:   "=",
+			Expression: &ast.IntegerLiteral{Value: 20}},
+		},
+		{"d[key] = 20;", &ast.AssignmentStatement{
+			Left: &ast.IndexExpression{
+				Left:  &ast.Identifier{Name: "d"},
+				Index: &ast.Identifier{Name: "key"},
+			},
+			Operator:   "=",
+			Expression: &ast.IntegerLiteral{Value: 20}},
+		},
+		{"m[\"rows\"][0] += 2;", &ast.AssignmentStatement{
+			Left: &ast.IndexExpression{
+				Left: &ast.IndexExpression{
+					Left:  &ast.Identifier{Name: "m"},
+					Index: &ast.StringLiteral{Value: "rows"},
+				},
+				Index: &ast.IntegerLiteral{Value: 0},
+			},
+			Operator:   "+=",
+			Expression: &ast.IntegerLiteral{Value: 2}},
+		},
+		{"x++;", &ast.AssignmentStatement{
+			Left:     &ast.Identifier{Name: "x"},
+			Operator: "++"},
+		},
+		{"x %= 3;", &ast.AssignmentStatement{
+			Left:       &ast.Identifier{Name: "x"},
+			Operator:   "%=",
+			Expression: &ast.IntegerLiteral{Value: 3}},
+		},
 	}
 	for _, tt := range tests {
 		l := lexer.New(tt.Line)
 		p := New(l)
 		stmt := p.parseStatement()
 
-		if len(p.errors) != 0 {
-			t.Errorf("expected zero errors, got %d", len(p.errors))
-			fmt.Println(p.errors)
+		if len(p.Errors()) != 0 {
+			t.Errorf("expected zero errors, got %d", len(p.Errors()))
+			fmt.Println(p.Errors())
 			fmt.Println(stmt)
 		}
 
@@ -359,8 +442,8 @@ func TestParseStatement(t *testing.T) {
 		// program := p.ParseProgram()
 		stmt := p.parseStatement()
 
-		if len(p.errors) != 1 {
-			t.Errorf("expected 1 error, got %d", len(p.errors))
+		if len(p.Errors()) != 1 {
+			t.Errorf("expected 1 error, got %d", len(p.Errors()))
 		}
 
 		if stmt != nil && !reflect.ValueOf(stmt).IsNil() {
@@ -397,7 +480,8 @@ func TestParseExpression(t *testing.T) {
 			Consequence: &ast.BlockStatement{
 				Statements: []ast.Statement{
 					&ast.AssignmentStatement{
-						Identifier: ast.Identifier{Name: "x"},
+						Left:     &ast.Identifier{Name: "x"},
+						Operator: "=",
 						Expression: &ast.InfixExpression{
 							Left:     &ast.Identifier{Name: "x"},
 							Operator: "+",
@@ -409,7 +493,8 @@ func TestParseExpression(t *testing.T) {
 			Alternative: &ast.BlockStatement{
 				Statements: []ast.Statement{
 					&ast.AssignmentStatement{
-						Identifier: ast.Identifier{Name: "x"},
+						Left:     &ast.Identifier{Name: "x"},
+						Operator: "=",
 						Expression: &ast.InfixExpression{
 							Left:     &ast.Identifier{Name: "x"},
 							Operator: "+",
@@ -462,7 +547,10 @@ func checkStatements(t *testing.T, stmt ast.Statement, ttStmt ast.Statement) {
 		checkExpressions(t, stmt.Function, ttStmt.Function)
 	case *ast.AssignmentStatement:
 		ttStmt := ttStmt.(*ast.AssignmentStatement)
-		checkExpressions(t, &stmt.Identifier, &ttStmt.Identifier)
+		if stmt.Operator != ttStmt.Operator {
+			t.Errorf("expected Operator '%s', got '%s'", ttStmt.Operator, stmt.Operator)
+		}
+		checkExpressions(t, stmt.Left, ttStmt.Left)
 		checkExpressions(t, stmt.Expression, ttStmt.Expression)
 	case *ast.ReturnStatement:
 		ttStmt := ttStmt.(*ast.ReturnStatement)
@@ -555,7 +643,7 @@ func checkExpressions(t *testing.T, exp ast.Expression, ttExp ast.Expression) {
 		checkStatements(t, exp.Body, ttExp.Body)
 	case *ast.CallExpression:
 		ttExp := ttExp.(*ast.CallExpression)
-		checkExpressions(t, &exp.Identifier, &ttExp.Identifier)
+		checkExpressions(t, exp.Function, ttExp.Function)
 		if len(exp.Arguments) != len(ttExp.Arguments) {
 			t.Errorf("expected %d arguments, got %d", len(ttExp.Arguments), len(exp.Arguments))
 		} else {
@@ -574,13 +662,10 @@ func checkExpressions(t *testing.T, exp ast.Expression, ttExp ast.Expression) {
 				checkExpressions(t, elem, ttElem)
 			}
 		}
-	case *ast.ArrayElementExpression:
-		ttExp := ttExp.(*ast.ArrayElementExpression)
-		checkExpressions(t, &exp.Identifier, &ttExp.Identifier)
-		if exp.Index != ttExp.Index {
-			t.Errorf("expected index %d, got %d", ttExp.Index, exp.Index)
-		}
-		checkExpressions(t, exp.Expression, ttExp.Expression)
+	case *ast.IndexExpression:
+		ttExp := ttExp.(*ast.IndexExpression)
+		checkExpressions(t, exp.Left, ttExp.Left)
+		checkExpressions(t, exp.Index, ttExp.Index)
 	case *ast.DictLiteral:
 		ttExp := ttExp.(*ast.DictLiteral)
 		if len(exp.Elements) != len(ttExp.Elements) {
@@ -591,12 +676,431 @@ func checkExpressions(t *testing.T, exp ast.Expression, ttExp ast.Expression) {
 				checkExpressions(t, v, vExp)
 			}
 		}
-	case *ast.DictElementExpression:
-		ttExp := ttExp.(*ast.DictElementExpression)
-		checkExpressions(t, &exp.Identifier, &ttExp.Identifier)
-		if exp.Key != ttExp.Key {
-			t.Errorf("expected key %q, got %q", ttExp.Key, exp.Key)
+	}
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	input := "int x = 1;\nint y = x\nint z = 3;"
+
+	l := lexer.New(input)
+	p := New(l)
+	p.ParseProgram()
+
+	if len(p.Errors()) == 0 {
+		t.Fatalf("expected errors, got none")
+	}
+
+	expected := "2:9: missing ';' after x"
+	if p.Errors()[0] != expected {
+		t.Fatalf("expected error %q, got %q", expected, p.Errors()[0])
+	}
+}
+
+func TestParseLoops(t *testing.T) {
+	tests := []struct {
+		Line   string
+		String string
+	}{
+		{"while (x < 10) { x = x + 1; }", "while ((x < 10)) { x = (x + 1); }"},
+		{"for (int i = 0; i < 10; i = i + 1) { x = x + i; }", "for (int i = 0; (i < 10); i = (i + 1)) { x = (x + i); }"},
+		{"for (i = 0; i < 10; f(i)) { }", "for (i = 0; (i < 10); f(i)) { }"},
+		{"for (;;) { break; }", "for (; ; ) { break; }"},
+		{"while (true) { if (x) { continue; } }", "while (true) { if x { continue; } ; }"},
+		{"for (int x in arr) { sum = sum + x; }", "for (int x in arr) { sum = (sum + x); }"},
+		{"for (string k, int v in d) { }", "for (string k, int v in d) { }"},
+		{"for (int i = 0; i < 10; i++) { x += i; }", "for (int i = 0; (i < 10); i++) { x += i; }"},
+		{"while (n > 0) { m[\"a\"][n] *= 2; n--; }", "while ((n > 0)) { m[\"a\"][n] *= 2; n--; }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.Line)
+		p := New(l)
+		program := p.ParseProgram()
+
+		if p.HasErrors() {
+			t.Fatalf("expected zero errors for %q, got %v", tt.Line, p.Errors())
+		}
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("expected 1 statement for %q, got %d", tt.Line, len(program.Statements))
+		}
+
+		if program.Statements[0].String() != tt.String {
+			t.Errorf("expected %q, got %q", tt.String, program.Statements[0].String())
+		}
+	}
+
+	errors := []string{
+		"break;",
+		"continue;",
+		"while (x) { int f() { break; } }",
+		"while x { }",
+		"for (int i = 0; i < 10) { }",
+		"for (return 1;;) { }",
+		"for (int x in ) { }",
+		"for (int x arr) { }",
+		"for (string k, v in d) { }",
+		"for (;; 1 = 2) { }",
+		"1 = 2;",
+		"f() += 1;",
+		"x++ 1;",
+	}
+
+	for _, line := range errors {
+		l := lexer.New(line)
+		p := New(l)
+		p.ParseProgram()
+
+		if !p.HasErrors() {
+			t.Errorf("expected errors for %q", line)
+		}
+	}
+}
+
+func TestParseVoidFunctions(t *testing.T) {
+	tests := []struct {
+		Line   string
+		String string
+	}{
+		{"void log(string s) { println(s); }", "void log(string s) { println(s); }"},
+		{"void f(int n) { if (n < 0) { return; } g(n); }", "void f(int n) { if (n < 0) { return; } ; g(n); }"},
+		{"void f() { int g() { return 1; } }", "void f() { int g() { return 1; } }"},
+		{"int f() { void g() { return; } return 1; }", "int f() { void g() { return; } return 1; }"},
+		{"return;", "return;"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.Line)
+		p := New(l)
+		program := p.ParseProgram()
+
+		if p.HasErrors() {
+			t.Fatalf("expected zero errors for %q, got %v", tt.Line, p.Errors())
+		}
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("expected 1 statement for %q, got %d", tt.Line, len(program.Statements))
+		}
+
+		if program.Statements[0].String() != tt.String {
+			t.Errorf("expected %q, got %q", tt.String, program.Statements[0].String())
+		}
+	}
+
+	errors := []struct {
+		Line  string
+		Error string
+		Code  string
+	}{
+		{"void f() { return 1; }", "1:19: void function cannot return a value", CodeInvalidReturn},
+		{"void f() { while (true) { return 1 + 2; } }", "1:34: void function cannot return a value", CodeInvalidReturn},
+		{"int f() { void g() { } return 1; }\nvoid h() { return f(); }", "2:19: void function cannot return a value", CodeInvalidReturn},
+		{"void x = 1;", "1:8: expected '(' after void x, only functions can be void", CodeUnexpectedToken},
+		{"void a[];", "1:7: expected '(' after void a, only functions can be void", CodeUnexpectedToken},
+		{"int f(void v) { return 1; }", "1:7: expected data type, got= void[VOID]", CodeUnexpectedToken},
+	}
+
+	for _, tt := range errors {
+		l := lexer.New(tt.Line)
+		p := New(l)
+		p.ParseProgram()
+
+		diagnostics := p.Diagnostics()
+		if len(diagnostics) != 1 {
+			t.Errorf("expected 1 error for %q, got %v", tt.Line, p.Errors())
+			continue
+		}
+		if diagnostics[0].String() != tt.Error || diagnostics[0].Code != tt.Code {
+			t.Errorf("expected %q [%s] for %q, got %q [%s]", tt.Error, tt.Code, tt.Line, diagnostics[0].String(), diagnostics[0].Code)
+		}
+	}
+}
+
+func TestParseFunctionLiterals(t *testing.T) {
+	tests := []struct {
+		Line   string
+		String string
+	}{
+		{"func add = int (int a, int b) { return a + b; };", "func add = int (int a, int b) { return (a + b); };"},
+		{"apply(bool (int a) { return a > 0; }, 1);", "apply(bool (int a) { return (a > 0); }, 1);"},
+		{"int (int x) { return x * 2; }(21);", "int (int x) { return (x * 2); }(21);"},
+		{"void () { println(); };", "void () { println(); };"},
+		{"func adder(int n) { return int (int x) { return x + n; }; }", "func adder(int n) { return int (int x) { return (x + n); }; }"},
+		{"adder(1)(2);", "adder(1)(2);"},
+		{"func ops[2] = [f, g]; ops[0](1);", "func ops[2] = [f, g];"},
+		{"int x = int(1.5) + int(float(y)) + char();", "int x = ((int(1.500000) + int(float(y))) + char());"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.Line)
+		p := New(l)
+		program := p.ParseProgram()
+
+		if p.HasErrors() {
+			t.Fatalf("expected zero errors for %q, got %v", tt.Line, p.Errors())
+		}
+
+		if program.Statements[0].String() != tt.String {
+			t.Errorf("expected %q, got %q", tt.String, program.Statements[0].String())
+		}
+	}
+
+	errors := []struct {
+		Line  string
+		Error string
+		Code  string
+	}{
+		{"func f = int (int a) 1;", "1:22: expected '{' in function literal, got=1", CodeUnexpectedToken},
+		{"string (s);", "1:9: expected data type, got= s[IDENT]", CodeUnexpectedToken},
+		{"void (int a) { return a; };", "1:23: void function cannot return a value", CodeInvalidReturn},
+		{"int (int a) { return a; }", "1:25: missing ';' after }", CodeMissingSemicolon},
+		{"func f = int (int a) { return; }(1 +);\nint y = 2;", "1:37: no prefix parse function for: )", CodeExpectedExpression},
+		{"func;", "1:5: expected identifier after: func; got=;", CodeUnexpectedToken},
+	}
+
+	for _, tt := range errors {
+		l := lexer.New(tt.Line)
+		p := New(l)
+		p.ParseProgram()
+
+		diagnostics := p.Diagnostics()
+		if len(diagnostics) != 1 {
+			t.Errorf("expected 1 error for %q, got %v", tt.Line, p.Errors())
+			continue
+		}
+		if diagnostics[0].String() != tt.Error || diagnostics[0].Code != tt.Code {
+			t.Errorf("expected %q [%s] for %q, got %q [%s]", tt.Error, tt.Code, tt.Line, diagnostics[0].String(), diagnostics[0].Code)
+		}
+	}
+}
+
+func TestParseOperatorPrecedence(t *testing.T) {
+	tests := []struct {
+		Line   string
+		String string
+	}{
+		{"a || b && c;", "(a || (b && c));"},
+		{"a && b == c;", "(a && (b == c));"},
+		{"a | b ^ c & d;", "(a | (b ^ (c & d)));"},
+		{"a & b == c;", "(a & (b == c));"},
+		{"a < b << c;", "(a < (b << c));"},
+		{"a << b + c;", "(a << (b + c));"},
+		{"a + b % c;", "(a + (b % c));"},
+		{"a * b ** c;", "(a * (b ** c));"},
+		{"a ** b ** c;", "(a ** (b ** c));"},
+		{"~a & b;", "(~a & b);"},
+		{"-a ** b;", "(-a ** b);"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.Line)
+		p := New(l)
+		program := p.ParseProgram()
+
+		if p.HasErrors() {
+			t.Fatalf("expected zero errors for %q, got %v", tt.Line, p.Errors())
+		}
+
+		if program.Statements[0].String() != tt.String {
+			t.Errorf("expected %q, got %q", tt.String, program.Statements[0].String())
+		}
+	}
+}
+
+func TestParseSliceExpression(t *testing.T) {
+	tests := []struct {
+		Line   string
+		String string
+	}{
+		{"a[1:3];", "a[1:3];"},
+		{"a[:3];", "a[:3];"},
+		{"a[1:];", "a[1:];"},
+		{"a[:];", "a[:];"},
+		{"s[-2:n + 1];", "s[-2:(n + 1)];"},
+		{"m[\"rows\"][1:][0];", "m[\"rows\"][1:][0];"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.Line)
+		p := New(l)
+		program := p.ParseProgram()
+
+		if p.HasErrors() {
+			t.Fatalf("expected zero errors for %q, got %v", tt.Line, p.Errors())
+		}
+
+		if program.Statements[0].String() != tt.String {
+			t.Errorf("expected %q, got %q", tt.String, program.Statements[0].String())
+		}
+	}
+
+	errors := []string{
+		"a[1:2:3];",
+		"a[1:2;",
+		"a[:] = [1];",
+	}
+
+	for _, line := range errors {
+		l := lexer.New(line)
+		p := New(l)
+		p.ParseProgram()
+
+		if !p.HasErrors() {
+			t.Errorf("expected errors for %q", line)
+		}
+	}
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	input := "int x = 1;\n\tint y = x\nint z = 3;"
+	p := New(lexer.New(input))
+	p.ParseProgram()
+
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d: %v", len(diagnostics), p.Errors())
+	}
+
+	d := diagnostics[0]
+	if d.Severity != SeverityError {
+		t.Errorf("expected severity error, got %s", d.Severity)
+	}
+	if d.Code != CodeMissingSemicolon {
+		t.Errorf("expected code %s, got %s", CodeMissingSemicolon, d.Code)
+	}
+	if d.Token.Literal != "x" {
+		t.Errorf("expected offending token x, got %s", d.Token.Literal)
+	}
+	if d.Start.String() != "2:10" || d.End.String() != "2:11" {
+		t.Errorf("expected range 2:10-2:11, got %s-%s", d.Start, d.End)
+	}
+	if d.String() != "2:10: missing ';' after x" {
+		t.Errorf("unexpected string %q", d.String())
+	}
+
+	var out strings.Builder
+	d.Render(&out, "test.src", input)
+	expected := "test.src:2:10: error: missing ';' after x [missing-semicolon]\n" +
+		" 2 | \tint y = x\n" +
+		"   | \t        ^\n" +
+		"hint: statements end with ';'\n"
+	if out.String() != expected {
+		t.Errorf("expected render\n%s\ngot\n%s", expected, out.String())
+	}
+
+	p = New(lexer.New("string s = \"abc\" \"def\";"))
+	p.ParseProgram()
+	d = p.Diagnostics()[0]
+	out.Reset()
+	d.Render(&out, "", "string s = \"abc\" \"def\";")
+	expected = "1:12: error: missing ';' after abc [missing-semicolon]\n" +
+		" 1 | string s = \"abc\" \"def\";\n" +
+		"   |            ^^^^^\n" +
+		"hint: statements end with ';'\n"
+	if out.String() != expected {
+		t.Errorf("expected render\n%s\ngot\n%s", expected, out.String())
+	}
+
+	// the carets cover the token as written, escapes included
+	src := "string s = \"a\\tb\" \"c\";"
+	p = New(lexer.New(src))
+	p.ParseProgram()
+	d = p.Diagnostics()[0]
+	out.Reset()
+	d.Render(&out, "", src)
+	expected = "1:12: error: missing ';' after a\\tb [missing-semicolon]\n" +
+		" 1 | string s = \"a\\tb\" \"c\";\n" +
+		"   |            ^^^^^^\n" +
+		"hint: statements end with ';'\n"
+	if out.String() != expected {
+		t.Errorf("expected render\n%s\ngot\n%s", expected, out.String())
+	}
+
+	// the carets cover the whole target of an invalid assignment
+	src = "int a[] = [1];\n  a[0:1] = [2];"
+	p = New(lexer.New(src))
+	p.ParseProgram()
+	d = p.Diagnostics()[0]
+	out.Reset()
+	d.Render(&out, "", src)
+	expected = "2:3: error: cannot assign to a[0:1] [invalid-assignment]\n" +
+		" 2 |   a[0:1] = [2];\n" +
+		"   |   ^^^^^^\n" +
+		"hint: only variables and elements of arrays and dicts can be assigned\n"
+	if out.String() != expected {
+		t.Errorf("expected render\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+func TestParseErrorRecovery(t *testing.T) {
+	tests := []struct {
+		input      string
+		errors     []string
+		statements int
+	}{
+		{
+			"int a = 1 +;\nint b = (2 * ;\nint c = a[;\nint d = 4;",
+			[]string{
+				"1:12: no prefix parse function for: ;",
+				"2:14: no prefix parse function for: ;",
+				"3:11: no prefix parse function for: ;",
+			},
+			1,
+		},
+		{
+			"int f(int x) {\n\tint y = x * ;\n\treturn x;\n}\nwhile (f(1) > ) { f(2); }\nfor (int i = 0; i < 3) { f(i); }\nf(3);",
+			[]string{
+				"2:14: no prefix parse function for: ;",
+				"5:15: no prefix parse function for: )",
+				"6:21: missing ';' after 3",
+			},
+			1,
+		},
+		{
+			"if (x > ) { x = 1; } else { x = 2; }\nwhile (true) {\n\tfoo(1, 2\n}\nint x = 1 2;\nint y = 3;",
+			[]string{
+				"1:9: no prefix parse function for: )",
+				"4:1: no prefix parse function for: }",
+				"5:9: missing ';' after 1",
+			},
+			1,
+		},
+		{
+			// a missing operand is not passed on to the call that follows
+			"println(x +, (2));\nint y = -)(1);\nint z = 3;",
+			[]string{
+				"1:12: no prefix parse function for: ,",
+				"2:10: no prefix parse function for: )",
+			},
+			1,
+		},
+		{
+			"int a = 1\nint b = 2\nint c = 3;",
+			[]string{
+				"1:9: missing ';' after 1",
+				"2:9: missing ';' after 2",
+			},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != len(tt.errors) {
+			t.Errorf("expected %d errors for %q, got %d: %v", len(tt.errors), tt.input, len(errors), errors)
+			continue
+		}
+		for i, err := range errors {
+			if err != tt.errors[i] {
+				t.Errorf("expected error %q, got %q", tt.errors[i], err)
+			}
+		}
+
+		if len(program.Statements) != tt.statements {
+			t.Errorf("expected %d statements for %q, got %d", tt.statements, tt.input, len(program.Statements))
 		}
-		checkExpressions(t, exp.Expression, ttExp.Expression)
 	}
 }
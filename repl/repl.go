@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/menxqk/my-interpreter/evaluator"
 	"github.com/menxqk/my-interpreter/lexer"
+	"github.com/menxqk/my-interpreter/object"
 	"github.com/menxqk/my-interpreter/parser"
+	"github.com/menxqk/my-interpreter/token"
+	"github.com/menxqk/my-interpreter/typecheck"
 )
 
 const (
@@ -20,31 +24,137 @@ var (
 	out = os.Stdout
 )
 
+// tokens that cannot end a statement, so the input continues on the next line
+var continuationTokens = map[string]bool{
+	token.PLUS:            true,
+	token.MINUS:           true,
+	token.SLASH:           true,
+	token.ASTERISK:        true,
+	token.PERCENT:         true,
+	token.POWER:           true,
+	token.BANG:            true,
+	token.TILDE:           true,
+	token.AMPERSAND:       true,
+	token.PIPE:            true,
+	token.CARET:           true,
+	token.LSHIFT:          true,
+	token.RSHIFT:          true,
+	token.ASSIGN:          true,
+	token.PLUS_ASSIGN:     true,
+	token.MINUS_ASSIGN:    true,
+	token.ASTERISK_ASSIGN: true,
+	token.SLASH_ASSIGN:    true,
+	token.PERCENT_ASSIGN:  true,
+	token.EQ:              true,
+	token.NOT_EQ:          true,
+	token.LT:              true,
+	token.LTE:             true,
+	token.GT:              true,
+	token.GTE:             true,
+	token.AND:             true,
+	token.OR:              true,
+	token.COMMA:           true,
+	token.COLON:           true,
+}
+
 func Start(debug bool) {
 	eval := evaluator.New()
+	// the checker keeps the globals declared by earlier inputs
+	checker := typecheck.New()
 
 	scanner := bufio.NewScanner(in)
 	for {
 		out.WriteString(PROMPT)
 
-		scanned := scanner.Scan()
+		input, scanned := readInput(scanner)
+		if strings.TrimSpace(input) != "" {
+			execute(eval, checker, input, debug)
+		}
+
 		if !scanned {
 			out.WriteString("\n")
 			return
 		}
+	}
+}
+
+// readInput reads lines until they form a complete input, showing the
+// DOTS prompt for every continuation line
+func readInput(scanner *bufio.Scanner) (string, bool) {
+	lines := []string{}
+	for {
+		if !scanner.Scan() {
+			return strings.Join(lines, "\n"), false
+		}
+		lines = append(lines, scanner.Text())
+
+		input := strings.Join(lines, "\n")
+		if !isIncomplete(input) {
+			return input, true
+		}
+
+		out.WriteString(DOTS)
+	}
+}
+
+func execute(eval *evaluator.Evaluator, checker *typecheck.Checker, input string, debug bool) {
+	l := lexer.New(input, debug)
+	p := parser.New(l, debug)
+	program := p.ParseProgram()
+
+	if p.HasErrors() {
+		printErrors(p.Errors())
+		return
+	}
+
+	if errs := checker.Check(program); len(errs) > 0 {
+		errors := []string{}
+		for _, err := range errs {
+			errors = append(errors, err.Error())
+		}
+		printErrors(errors)
+		return
+	}
+
+	res := eval.Eval(program)
+	switch res.(type) {
+	case *object.Void:
+		// calls to void functions have no value to print
+		return
+	case *object.Error:
+		checker.Rollback()
+	}
+	out.WriteString(res.Inspect() + "\n")
+}
 
-		line := scanner.Text()
-		l := lexer.New(line, debug)
-		p := parser.New(l, debug)
-		program := p.ParseProgram()
+// isIncomplete reports whether input has unbalanced braces, brackets or
+// parentheses, an unterminated string or ends with an operator
+func isIncomplete(input string) bool {
+	l := lexer.New(input)
 
-		if p.HasErrors() {
-			printErrors(p.Errors())
-		} else {
-			res := eval.Eval(program)
-			out.WriteString(res.Inspect() + "\n")
+	depth := 0
+	last := token.Token{}
+	for {
+		tok := l.NextToken()
+		if tok.Type == token.EOF {
+			break
 		}
+
+		switch tok.Type {
+		case token.LPAREN, token.LBRACKET, token.LBRACE:
+			depth++
+		case token.RPAREN, token.RBRACKET, token.RBRACE:
+			depth--
+		case token.ILLEGAL:
+			if strings.HasPrefix(tok.Literal, "\"") {
+				return true
+			}
+		}
+
+		last = tok
 	}
+
+	return depth > 0 || continuationTokens[last.Type]
 }
 
 func printErrors(errors []string) {
@@ -0,0 +1,33 @@
+package repl
+
+import "testing"
+
+func TestIsIncomplete(t *testing.T) {
+	tests := []struct {
+		Input      string
+		Incomplete bool
+	}{
+		{"int x = 1;", false},
+		{"x;", false},
+		{"", false},
+		{"int add(int a, int b) {", true},
+		{"int add(int a, int b) {\n return a + b;", true},
+		{"int add(int a, int b) {\n return a + b;\n}", false},
+		{"if (x > 1) { x = 2; }", false},
+		{"int a[] = [1, 2,", true},
+		{"int a[] = [1, 2,\n 3];", false},
+		{"add(1,", true},
+		{"string s = \"a long", true},
+		{"string s = \"a long\nstring\";", false},
+		{"int x = 1 +", true},
+		{"int x =", true},
+		{"dict d = {\"one\":", true},
+		{"}", false},
+	}
+
+	for _, tt := range tests {
+		if got := isIncomplete(tt.Input); got != tt.Incomplete {
+			t.Errorf("expected %t for %q, got %t", tt.Incomplete, tt.Input, got)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package token
 
+import "fmt"
+
 const (
 	EOF     = "EOF"
 	ILLEGAL = "ILLEGAL"
@@ -12,6 +14,9 @@ const (
 	CHAR_TYPE   = "CHAR"
 	STRING_TYPE = "STRING"
 	DICT_TYPE   = "DICT"
+	BOOL_TYPE   = "BOOLEAN"
+	VOID_TYPE   = "VOID"
+	FUNC_TYPE   = "FUNCTION"
 
 	// Values
 	INT_VALUE    = "INT_VALUE"
@@ -20,18 +25,35 @@ const (
 	STRING_VALUE = "STRING_VALUE"
 
 	// Operators
-	PLUS     = "+"
-	MINUS    = "-"
-	SLASH    = "/"
-	ASTERISK = "*"
-	BANG     = "!"
-	ASSIGN   = "="
-	EQ       = "=="
-	NOT_EQ   = "!="
-	LT       = "<"
-	LTE      = "<="
-	GT       = ">"
-	GTE      = ">="
+	PLUS            = "+"
+	MINUS           = "-"
+	SLASH           = "/"
+	ASTERISK        = "*"
+	PERCENT         = "%"
+	POWER           = "**"
+	BANG            = "!"
+	TILDE           = "~"
+	AMPERSAND       = "&"
+	PIPE            = "|"
+	CARET           = "^"
+	LSHIFT          = "<<"
+	RSHIFT          = ">>"
+	ASSIGN          = "="
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
+	PERCENT_ASSIGN  = "%="
+	INCREMENT       = "++"
+	DECREMENT       = "--"
+	EQ              = "=="
+	NOT_EQ          = "!="
+	LT              = "<"
+	LTE             = "<="
+	GT              = ">"
+	GTE             = ">="
+	AND             = "&&"
+	OR              = "||"
 
 	// Delimiters
 	COMMA     = ","
@@ -45,31 +67,60 @@ const (
 	RBRACE    = "}"
 
 	// Keywords
-	IF     = "IF"
-	ELSE   = "ELSE"
-	RETURN = "RETURN"
-	TRUE   = "TRUE"
-	FALSE  = "FALSE"
-	NULL   = "NULL"
+	IF       = "IF"
+	ELSE     = "ELSE"
+	RETURN   = "RETURN"
+	TRUE     = "TRUE"
+	FALSE    = "FALSE"
+	NULL     = "NULL"
+	WHILE    = "WHILE"
+	FOR      = "FOR"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+	IN       = "IN"
 )
 
+type Position struct {
+	Offset int // byte offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // column number in characters, starting at 1
+}
+
+func (p Position) IsValid() bool { return p.Line > 0 }
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
 type Token struct {
 	Type    string
 	Literal string
+	Pos     Position
+	End     Position // position right after the token in the source
 }
 
 var keywords = map[string]string{
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
-	"true":   TRUE,
-	"null":   NULL,
-	"false":  FALSE,
-	"int":    INT_TYPE,
-	"float":  FLOAT_TYPE,
-	"char":   CHAR_TYPE,
-	"string": STRING_TYPE,
-	"dict":   DICT_TYPE,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"true":     TRUE,
+	"null":     NULL,
+	"false":    FALSE,
+	"while":    WHILE,
+	"for":      FOR,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"in":       IN,
+	"int":      INT_TYPE,
+	"float":    FLOAT_TYPE,
+	"char":     CHAR_TYPE,
+	"string":   STRING_TYPE,
+	"dict":     DICT_TYPE,
+	"bool":     BOOL_TYPE,
+	"void":     VOID_TYPE,
+	"func":     FUNC_TYPE,
 }
 
 var dataTypes = map[string]string{
@@ -78,6 +129,20 @@ var dataTypes = map[string]string{
 	"char":   CHAR_TYPE,
 	"string": STRING_TYPE,
 	"dict":   DICT_TYPE,
+	"bool":   BOOL_TYPE,
+	"func":   FUNC_TYPE,
+}
+
+// CompoundOperators maps compound assignment operators to the binary
+// operator they apply
+var CompoundOperators = map[string]string{
+	PLUS_ASSIGN:     PLUS,
+	MINUS_ASSIGN:    MINUS,
+	ASTERISK_ASSIGN: ASTERISK,
+	SLASH_ASSIGN:    SLASH,
+	PERCENT_ASSIGN:  PERCENT,
+	INCREMENT:       PLUS,
+	DECREMENT:       MINUS,
 }
 
 func LookupIdentType(ident string) string {
@@ -0,0 +1,109 @@
+package typecheck
+
+import (
+	"github.com/menxqk/my-interpreter/ast"
+)
+
+// builtinFn checks a call to a builtin function given the types of its
+// arguments and returns the type of its result
+type builtinFn func(c *Checker, call *ast.CallExpression, args []*Type) *Type
+
+var builtins = map[string]builtinFn{
+	"len":     checkLen,
+	"print":   checkPrint,
+	"println": checkPrint,
+	"push":    checkPush,
+	"pop":     checkPop,
+	"keys":    returning(ArrayOf(String), Dict),
+	"values":  returning(ArrayOf(Unknown), Dict),
+	"has":     returning(Bool, Dict, String),
+	"delete":  returning(Unknown, Dict, String),
+	"type":    returning(String, Unknown),
+	"str":     returning(String, Unknown),
+	"int":     converting(Int, Int, Float, Char, String),
+	"float":   converting(Float, Int, Float, String),
+	"char":    converting(Char, Int, Char, String),
+}
+
+// anyArray is the type of parameters taking arrays of any type
+var anyArray = ArrayOf(Unknown)
+
+// checkArgs checks the number and types of the arguments to a builtin
+// and reports whether they are right
+func (c *Checker) checkArgs(call *ast.CallExpression, args []*Type, params ...*Type) bool {
+	name := call.Function.String()
+	if len(args) != len(params) {
+		c.errorf(call.Pos, "wrong number of arguments to %s: %d, expected %d", name, len(args), len(params))
+		return false
+	}
+
+	ok := true
+	for i, param := range params {
+		if !param.Accepts(args[i]) {
+			c.errorf(call.Arguments[i].Position(), "%s: argument %d must be %s, got %s", name, i+1, param, args[i])
+			ok = false
+		}
+	}
+	return ok
+}
+
+// returning returns the check of a builtin taking params and returning result
+func returning(result *Type, params ...*Type) builtinFn {
+	return func(c *Checker, call *ast.CallExpression, args []*Type) *Type {
+		c.checkArgs(call, args, params...)
+		return result
+	}
+}
+
+// converting returns the check of a builtin converting values of the
+// types from to result
+func converting(result *Type, from ...*Type) builtinFn {
+	return func(c *Checker, call *ast.CallExpression, args []*Type) *Type {
+		if !c.checkArgs(call, args, Unknown) || args[0].IsUnknown() {
+			return result
+		}
+
+		for _, t := range from {
+			if args[0].is(t) {
+				return result
+			}
+		}
+		c.errorf(call.Arguments[0].Position(), "%s: cannot convert %s to %s", call.Function.String(), args[0], result)
+		return result
+	}
+}
+
+func checkLen(c *Checker, call *ast.CallExpression, args []*Type) *Type {
+	if !c.checkArgs(call, args, Unknown) {
+		return Int
+	}
+
+	switch arg := args[0]; {
+	case arg.IsUnknown(), arg.IsArray(), arg.is(String), arg.is(Dict):
+	default:
+		c.errorf(call.Arguments[0].Position(), "len: argument must be %s, %s or %s, got %s", String, anyArray, Dict, arg)
+	}
+	return Int
+}
+
+func checkPrint(c *Checker, call *ast.CallExpression, args []*Type) *Type {
+	return Null
+}
+
+func checkPush(c *Checker, call *ast.CallExpression, args []*Type) *Type {
+	if !c.checkArgs(call, args, anyArray, Unknown) || args[0].IsUnknown() {
+		return anyArray
+	}
+
+	if !args[0].Elem.Accepts(args[1]) {
+		c.errorf(call.Arguments[1].Position(), "cannot assign %s to %s array", args[1], args[0].Elem)
+	}
+	return args[0]
+}
+
+func checkPop(c *Checker, call *ast.CallExpression, args []*Type) *Type {
+	if !c.checkArgs(call, args, anyArray) || args[0].IsUnknown() {
+		return Unknown
+	}
+	return args[0].Elem
+}
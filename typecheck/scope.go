@@ -0,0 +1,50 @@
+package typecheck
+
+// scope holds the names declared in a block, like the environments the
+// evaluator creates for it
+type scope struct {
+	outer *scope
+	names map[string]*entry
+
+	// number of functions enclosing the scope
+	level int
+}
+
+type entry struct {
+	typ *Type
+
+	// a name is known from the start of its block but only declared
+	// once its declaration runs, functions called later may use it
+	// before that
+	declared bool
+}
+
+func newScope(outer *scope, level int) *scope {
+	return &scope{outer: outer, names: map[string]*entry{}, level: level}
+}
+
+// hoist makes name known in the scope before its declaration runs
+func (s *scope) hoist(name string, t *Type) {
+	if _, ok := s.names[name]; !ok {
+		s.names[name] = &entry{typ: t}
+	}
+}
+
+func (s *scope) declare(name string, t *Type) {
+	s.names[name] = &entry{typ: t, declared: true}
+}
+
+// lookup finds the type of name as seen from code running in a
+// function nested level times
+func (s *scope) lookup(name string, level int) (*Type, bool) {
+	for sc := s; sc != nil; sc = sc.outer {
+		e, ok := sc.names[name]
+		if !ok {
+			continue
+		}
+		if e.declared || sc.level < level {
+			return e.typ, true
+		}
+	}
+	return nil, false
+}
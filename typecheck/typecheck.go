@@ -0,0 +1,112 @@
+// Package typecheck checks the types of a program before it is
+// evaluated, so that mistakes in code that rarely runs are found up
+// front. Values whose type is only known at runtime, like dict
+// elements, are left for the evaluator to check.
+package typecheck
+
+import (
+	"fmt"
+
+	"github.com/menxqk/my-interpreter/ast"
+	"github.com/menxqk/my-interpreter/token"
+)
+
+// Error is a type error found at Pos
+type Error struct {
+	Pos     token.Position
+	Message string
+}
+
+// Error returns the error as "line:col: message"
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+type Checker struct {
+	globals *scope
+	scope   *scope
+
+	// type of the function being checked, nil at the top level
+	fn *Type
+
+	types  map[ast.Expression]*Type
+	errors []*Error
+
+	// globals before the last program checked
+	saved map[string]*entry
+}
+
+func New() *Checker {
+	globals := newScope(nil, 0)
+	return &Checker{
+		globals: globals,
+		scope:   globals,
+		types:   map[ast.Expression]*Type{},
+	}
+}
+
+// Check checks program with a new Checker
+func Check(program *ast.Program) []*Error {
+	return New().Check(program)
+}
+
+// Declare declares a global name provided by the host program, like the
+// variables set and the builtins registered on the evaluator
+func (c *Checker) Declare(name string, t *Type) {
+	c.globals.declare(name, t)
+}
+
+// Check checks program and returns the errors found, in source order.
+// Its global declarations stay known to later calls, unless errors are
+// found, as the program does not run then, or Rollback is called.
+func (c *Checker) Check(program *ast.Program) []*Error {
+	c.errors = []*Error{}
+	c.types = map[ast.Expression]*Type{}
+
+	c.saved = map[string]*entry{}
+	for name, e := range c.globals.names {
+		c.saved[name] = e
+	}
+
+	c.hoist(program.Statements)
+	for _, stmt := range program.Statements {
+		c.checkStatement(stmt)
+	}
+
+	if len(c.errors) > 0 {
+		c.Rollback()
+	}
+	return c.errors
+}
+
+// Rollback forgets the global declarations of the last program checked,
+// for when it failed to run
+func (c *Checker) Rollback() {
+	if c.saved != nil {
+		c.globals.names = c.saved
+		c.saved = nil
+	}
+}
+
+// TypeOf returns the type inferred for exp, or nil if it was not checked
+func (c *Checker) TypeOf(exp ast.Expression) *Type {
+	return c.types[exp]
+}
+
+func (c *Checker) errorf(pos token.Position, format string, a ...interface{}) {
+	c.errors = append(c.errors, &Error{Pos: pos, Message: fmt.Sprintf(format, a...)})
+}
+
+// level returns the number of functions enclosing the code being checked
+func (c *Checker) level() int {
+	return c.scope.level
+}
+
+// checkInScope checks fn in a new scope enclosed by the current one
+func (c *Checker) checkInScope(level int, fn func()) {
+	outer := c.scope
+	c.scope = newScope(outer, level)
+	defer func() { c.scope = outer }()
+
+	fn()
+}
@@ -0,0 +1,327 @@
+package typecheck
+
+import (
+	"sort"
+
+	"github.com/menxqk/my-interpreter/ast"
+	"github.com/menxqk/my-interpreter/token"
+)
+
+// expr checks exp, whose value is used, and returns its type
+func (c *Checker) expr(exp ast.Expression) *Type {
+	t := c.exprType(exp)
+	c.types[exp] = t
+
+	if t.is(Void) {
+		c.errorf(exp.Position(), "%s (no value) used as value", exp.String())
+		return Unknown
+	}
+	return t
+}
+
+func (c *Checker) exprType(exp ast.Expression) *Type {
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		return c.identifierType(exp)
+	case *ast.PrefixExpression:
+		return c.prefixType(exp)
+	case *ast.GroupedExpression:
+		return c.expr(exp.Expression)
+	case *ast.InfixExpression:
+		if exp.Operator == "&&" || exp.Operator == "||" {
+			c.checkCondition(exp.Left)
+			c.checkCondition(exp.Right)
+			return Bool
+		}
+		return c.infixType(exp.Pos, exp.Operator, c.expr(exp.Left), c.expr(exp.Right))
+	case *ast.IfExpression:
+		c.checkCondition(exp.Condition)
+		c.checkBlock(exp.Consequence)
+		c.checkBlock(exp.Alternative)
+		return Unknown
+	case *ast.FunctionExpression:
+		return c.functionType(exp)
+	case *ast.CallExpression:
+		return c.callType(exp)
+	case *ast.IndexExpression:
+		return c.indexType(exp, c.expr(exp.Left), c.expr(exp.Index))
+	case *ast.SliceExpression:
+		return c.sliceType(exp)
+
+	// Literals
+	case *ast.IntegerLiteral:
+		return Int
+	case *ast.FloatLiteral:
+		return Float
+	case *ast.CharLiteral:
+		return Char
+	case *ast.StringLiteral:
+		return String
+	case *ast.BooleanLiteral:
+		return Bool
+	case *ast.NullLiteral:
+		return Null
+	case *ast.ArrayLiteral:
+		return c.arrayLiteralType(exp)
+	case *ast.DictLiteral:
+		return c.dictLiteralType(exp)
+	default:
+		return Unknown
+	}
+}
+
+func (c *Checker) identifierType(ident *ast.Identifier) *Type {
+	if t, ok := c.scope.lookup(ident.Name, c.level()); ok {
+		return t
+	}
+	if _, ok := builtins[ident.Name]; ok {
+		return Builtin
+	}
+
+	c.errorf(ident.Pos, "undeclared name %q", ident.Name)
+	return Unknown
+}
+
+func (c *Checker) prefixType(exp *ast.PrefixExpression) *Type {
+	right := c.expr(exp.Expression)
+
+	switch {
+	case exp.Operator == "!":
+		return Bool
+	case right.IsUnknown():
+		return Unknown
+	case exp.Operator == "-" && isNumeric(right):
+		return right
+	case exp.Operator == "~" && right.is(Int):
+		return Int
+	default:
+		c.errorf(exp.Pos, "unknown operator: %s%s", exp.Operator, right)
+		return Unknown
+	}
+}
+
+var comparisonOperators = map[string]bool{
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+}
+
+var arithmeticOperators = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true, "**": true,
+}
+
+var bitwiseOperators = map[string]bool{
+	"&": true, "|": true, "^": true, "<<": true, ">>": true,
+}
+
+// infixType returns the type of the result of a binary operator, the
+// operands combine as they do in the evaluator
+func (c *Checker) infixType(pos token.Position, operator string, left, right *Type) *Type {
+	comparison := comparisonOperators[operator]
+	if left.IsUnknown() || right.IsUnknown() {
+		if comparison {
+			return Bool
+		}
+		return Unknown
+	}
+
+	var result *Type
+	switch {
+	case isNumeric(left) && isNumeric(right):
+		switch {
+		case comparison:
+			result = Bool
+		case left.is(Int) && right.is(Int) && (arithmeticOperators[operator] || bitwiseOperators[operator]):
+			result = Int
+		case arithmeticOperators[operator]:
+			result = Float
+		}
+	case isText(left) && isText(right):
+		if comparison {
+			result = Bool
+		} else if operator == "+" {
+			result = String
+		}
+	case left.IsArray() && right.IsArray() && left.Accepts(right):
+		if comparison {
+			result = Bool
+		} else if operator == "+" || operator == "-" {
+			result = left
+			if left.Elem.IsUnknown() {
+				result = right
+			}
+		}
+	case left.is(Dict) && right.is(Dict):
+		if comparison {
+			result = Bool
+		} else if operator == "+" || operator == "-" {
+			result = Dict
+		}
+	case left.is(Bool) && right.is(Bool):
+		if operator == "==" || operator == "!=" {
+			result = Bool
+		}
+	}
+
+	if result == nil {
+		c.errorf(pos, "illegal operation %s %s %s", left, operator, right)
+		return Unknown
+	}
+	return result
+}
+
+// functionType returns the type of fn and checks its body
+func (c *Checker) functionType(fn *ast.FunctionExpression) *Type {
+	t := functionTypeOf(fn)
+	if fn.Body == nil {
+		return t
+	}
+
+	outerFn := c.fn
+	c.fn = t
+	defer func() { c.fn = outerFn }()
+
+	c.checkInScope(c.level()+1, func() {
+		for _, param := range fn.Parameters {
+			c.scope.declare(param.Name, TypeOf(param.Type))
+		}
+		c.checkBlock(fn.Body)
+	})
+
+	if !t.Result.is(Void) && !terminates(fn.Body.Statements) {
+		if fn.Identifier.Name == "" {
+			c.errorf(fn.Pos, "missing return at the end of function literal")
+		} else {
+			c.errorf(fn.Identifier.Pos, "missing return at the end of function %q", fn.Identifier.Name)
+		}
+	}
+
+	return t
+}
+
+// functionTypeOf returns the type of fn from its declaration
+func functionTypeOf(fn *ast.FunctionExpression) *Type {
+	params := []*Type{}
+	for _, param := range fn.Parameters {
+		params = append(params, TypeOf(param.Type))
+	}
+	return FunctionOf(params, TypeOf(fn.Identifier.Type))
+}
+
+func (c *Checker) callType(exp *ast.CallExpression) *Type {
+	args := []*Type{}
+	for _, arg := range exp.Arguments {
+		args = append(args, c.expr(arg))
+	}
+
+	name := exp.Function.String()
+	var fn *Type
+	if ident, ok := exp.Function.(*ast.Identifier); ok {
+		t, ok := c.scope.lookup(ident.Name, c.level())
+		if !ok {
+			if builtin, ok := builtins[ident.Name]; ok {
+				return builtin(c, exp, args)
+			}
+			c.errorf(ident.Pos, "undeclared name %q", ident.Name)
+			return Unknown
+		}
+		c.types[ident] = t
+		fn = t
+	} else {
+		fn = c.expr(exp.Function)
+	}
+
+	switch {
+	case fn.IsUnknown() || fn.is(Builtin):
+		return Unknown
+	case !fn.IsFunction():
+		c.errorf(exp.Function.Position(), "%q is not a function, got %s", name, fn)
+		return Unknown
+	case !fn.HasSignature():
+		return Unknown
+	}
+
+	if len(args) != len(fn.Params) {
+		c.errorf(exp.Pos, "wrong number of arguments to %q: %d, expected %d", name, len(args), len(fn.Params))
+		return fn.Result
+	}
+	for i, arg := range args {
+		if !fn.Params[i].Accepts(arg) {
+			c.errorf(exp.Arguments[i].Position(), "wrong type for argument %d to %q, got %s, expected %s", i+1, name, arg, fn.Params[i])
+		}
+	}
+
+	return fn.Result
+}
+
+// indexType returns the type of the element of left at index
+func (c *Checker) indexType(exp *ast.IndexExpression, left, index *Type) *Type {
+	switch {
+	case left.IsUnknown():
+		return Unknown
+	case left.IsArray():
+		c.checkIndex(exp.Index, index, "array index")
+		return left.Elem
+	case left.is(String):
+		c.checkIndex(exp.Index, index, "string index")
+		return Char
+	case left.is(Dict):
+		if !String.Accepts(index) {
+			c.errorf(exp.Index.Position(), "dict key must be %s, got %s", String, index)
+		}
+		return Unknown
+	default:
+		c.errorf(exp.Pos, "%q not an array, dict or string, got %s", exp.Left.String(), left)
+		return Unknown
+	}
+}
+
+func (c *Checker) sliceType(exp *ast.SliceExpression) *Type {
+	left := c.expr(exp.Left)
+	for _, bound := range []ast.Expression{exp.Start, exp.End} {
+		if bound != nil {
+			c.checkIndex(bound, c.expr(bound), "slice index")
+		}
+	}
+
+	switch {
+	case left.IsUnknown(), left.IsArray(), left.is(String):
+		return left
+	default:
+		c.errorf(exp.Pos, "%q not an array or string, got %s", exp.Left.String(), left)
+		return Unknown
+	}
+}
+
+// checkIndex checks that exp, of type t, can be used as an index
+func (c *Checker) checkIndex(exp ast.Expression, t *Type, what string) {
+	if !Int.Accepts(t) {
+		c.errorf(exp.Position(), "%s must be %s, got %s", what, Int, t)
+	}
+}
+
+func (c *Checker) arrayLiteralType(lit *ast.ArrayLiteral) *Type {
+	elem := Unknown
+	for i, e := range lit.Elements {
+		t := c.expr(e)
+		if i == 0 {
+			elem = t
+			continue
+		}
+		if !elem.Accepts(t) {
+			c.errorf(e.Position(), "cannot mix %s and %s in array", elem, t)
+		}
+	}
+	return ArrayOf(elem)
+}
+
+func (c *Checker) dictLiteralType(lit *ast.DictLiteral) *Type {
+	keys := []string{}
+	for k := range lit.Elements {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		c.expr(lit.Elements[k])
+	}
+	return Dict
+}
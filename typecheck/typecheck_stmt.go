@@ -0,0 +1,319 @@
+package typecheck
+
+import (
+	"github.com/menxqk/my-interpreter/ast"
+	"github.com/menxqk/my-interpreter/token"
+)
+
+func (c *Checker) checkStatement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.ExpressionStatement:
+		// the value of an expression statement is not used, it can be
+		// a call to a void function
+		c.types[stmt.Expression] = c.exprType(stmt.Expression)
+	case *ast.VariableDeclarationStatement:
+		c.checkVariableDeclaration(stmt)
+	case *ast.ArrayDeclarationStatement:
+		c.checkArrayDeclaration(stmt)
+	case *ast.FunctionDeclarationStatement:
+		c.checkFunctionDeclaration(stmt)
+	case *ast.AssignmentStatement:
+		c.checkAssignment(stmt)
+	case *ast.ReturnStatement:
+		c.checkReturn(stmt)
+	case *ast.WhileStatement:
+		c.checkCondition(stmt.Condition)
+		c.checkInScope(c.level(), func() { c.checkBlock(stmt.Body) })
+	case *ast.ForStatement:
+		c.checkFor(stmt)
+	case *ast.ForInStatement:
+		c.checkForIn(stmt)
+	}
+}
+
+// checkBlock checks the statements of block in the current scope
+func (c *Checker) checkBlock(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+
+	c.hoist(block.Statements)
+	for _, stmt := range block.Statements {
+		c.checkStatement(stmt)
+	}
+}
+
+// hoist makes the names declared by stmts known to the current scope, so
+// that functions can use the ones declared after them
+func (c *Checker) hoist(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *ast.VariableDeclarationStatement:
+			c.scope.hoist(stmt.Identifier.Name, TypeOf(stmt.Identifier.Type))
+		case *ast.ArrayDeclarationStatement:
+			c.scope.hoist(stmt.Identifier.Name, arrayType(stmt))
+		case *ast.FunctionDeclarationStatement:
+			if fn, ok := stmt.Function.(*ast.FunctionExpression); ok {
+				c.scope.hoist(fn.Identifier.Name, functionTypeOf(fn))
+			}
+		case *ast.ExpressionStatement:
+			// if blocks declare their names in the enclosing scope
+			if ifExp, ok := stmt.Expression.(*ast.IfExpression); ok {
+				if ifExp.Consequence != nil {
+					c.hoist(ifExp.Consequence.Statements)
+				}
+				if ifExp.Alternative != nil {
+					c.hoist(ifExp.Alternative.Statements)
+				}
+			}
+		}
+	}
+}
+
+func (c *Checker) checkVariableDeclaration(stmt *ast.VariableDeclarationStatement) {
+	t := TypeOf(stmt.Identifier.Type)
+	if stmt.Expression != nil {
+		c.checkValue(t, stmt.Expression, true)
+	}
+	c.scope.declare(stmt.Identifier.Name, t)
+}
+
+func (c *Checker) checkArrayDeclaration(stmt *ast.ArrayDeclarationStatement) {
+	t := arrayType(stmt)
+	if stmt.Expression != nil {
+		c.checkValue(t, stmt.Expression, true)
+
+		lit, ok := stmt.Expression.(*ast.ArrayLiteral)
+		if ok && stmt.Size > 0 && len(lit.Elements) > stmt.Size {
+			c.errorf(lit.Pos, "%d elements exceed array capacity %d", len(lit.Elements), stmt.Size)
+		}
+	}
+	c.scope.declare(stmt.Identifier.Name, t)
+}
+
+// arrayType returns the type of the array declared by stmt
+func arrayType(stmt *ast.ArrayDeclarationStatement) *Type {
+	t := ArrayOf(TypeOf(stmt.Identifier.Type))
+	for i := 1; i < stmt.Dimensions; i++ {
+		t = ArrayOf(t)
+	}
+	return t
+}
+
+func (c *Checker) checkFunctionDeclaration(stmt *ast.FunctionDeclarationStatement) {
+	fn, ok := stmt.Function.(*ast.FunctionExpression)
+	if !ok {
+		return
+	}
+
+	// declared before its body is checked, for recursive calls
+	c.scope.declare(fn.Identifier.Name, functionTypeOf(fn))
+	c.expr(fn)
+}
+
+func (c *Checker) checkAssignment(stmt *ast.AssignmentStatement) {
+	var target *Type
+	switch left := stmt.Left.(type) {
+	case *ast.Identifier:
+		t, ok := c.scope.lookup(left.Name, c.level())
+		if !ok {
+			c.errorf(left.Pos, "%q not declared", left.Name)
+			return
+		}
+		c.types[left] = t
+		target = t
+	case *ast.IndexExpression:
+		target = c.indexTarget(left)
+	default:
+		c.errorf(stmt.Pos, "cannot assign to %s", stmt.Left.String())
+		return
+	}
+
+	operator, compound := token.CompoundOperators[stmt.Operator]
+	if !compound {
+		c.checkValue(target, stmt.Expression, false)
+		return
+	}
+
+	right := Int
+	if stmt.Expression != nil {
+		right = c.expr(stmt.Expression)
+	}
+	result := c.infixType(stmt.Pos, operator, target, right)
+	if !target.Accepts(result) {
+		c.errorf(stmt.Pos, "cannot assign %s to %s", result, target)
+	}
+}
+
+// indexTarget returns the type of the element exp assigns to
+func (c *Checker) indexTarget(exp *ast.IndexExpression) *Type {
+	left := c.expr(exp.Left)
+	index := c.expr(exp.Index)
+	c.types[exp] = c.indexType(exp, left, index)
+
+	switch left.Name {
+	case String.Name:
+		c.errorf(exp.Pos, "cannot assign to %s index", String)
+		return Unknown
+	case Dict.Name:
+		// dicts hold values of any type
+		return Unknown
+	default:
+		return c.types[exp]
+	}
+}
+
+// checkValue checks that the value of exp can be stored where values of
+// type want are expected, null being accepted by declarations. Array
+// literals are checked element by element.
+func (c *Checker) checkValue(want *Type, exp ast.Expression, nullable bool) {
+	if lit, ok := exp.(*ast.ArrayLiteral); ok && want.IsArray() {
+		for _, elem := range lit.Elements {
+			if _, ok := elem.(*ast.ArrayLiteral); ok && want.Elem.IsArray() {
+				c.checkValue(want.Elem, elem, false)
+				continue
+			}
+			if t := c.expr(elem); !want.Elem.Accepts(t) {
+				c.errorf(elem.Position(), "cannot assign %s to %s array", t, want.Elem)
+			}
+		}
+		c.types[lit] = want
+		return
+	}
+
+	t := c.expr(exp)
+	if nullable && t.is(Null) {
+		return
+	}
+	if !want.Accepts(t) {
+		c.errorf(exp.Position(), "cannot assign %s to %s", t, want)
+	}
+}
+
+func (c *Checker) checkReturn(stmt *ast.ReturnStatement) {
+	if stmt.ReturnValue == nil {
+		if c.fn != nil && !c.fn.Result.is(Void) {
+			c.errorf(stmt.Pos, "missing return value in function returning %s", c.fn.Result)
+		}
+		return
+	}
+
+	t := c.expr(stmt.ReturnValue)
+	switch {
+	case c.fn == nil:
+	case c.fn.Result.is(Void):
+		c.errorf(stmt.ReturnValue.Position(), "void function cannot return a value")
+	case !c.fn.Result.Accepts(t):
+		c.errorf(stmt.ReturnValue.Position(), "cannot return %s from function returning %s", t, c.fn.Result)
+	}
+}
+
+func (c *Checker) checkFor(stmt *ast.ForStatement) {
+	// variables declared in the init statement are scoped to the loop
+	c.checkInScope(c.level(), func() {
+		if stmt.Init != nil {
+			c.hoist([]ast.Statement{stmt.Init})
+			c.checkStatement(stmt.Init)
+		}
+		if stmt.Condition != nil {
+			c.checkCondition(stmt.Condition)
+		}
+		if stmt.Post != nil {
+			c.checkStatement(stmt.Post)
+		}
+		c.checkInScope(c.level(), func() { c.checkBlock(stmt.Body) })
+	})
+}
+
+func (c *Checker) checkForIn(stmt *ast.ForInStatement) {
+	iterable := c.expr(stmt.Iterable)
+
+	key, value := Unknown, Unknown
+	switch iterable.Name {
+	case ANY:
+	case String.Name:
+		key, value = Int, Char
+	case Dict.Name:
+		key = String
+		// a dict loop with a single variable walks the keys
+		if stmt.Key == nil {
+			value = String
+		}
+	default:
+		if iterable.IsArray() {
+			key, value = Int, iterable.Elem
+			break
+		}
+		c.errorf(stmt.Iterable.Position(), "cannot iterate over %s", iterable)
+	}
+
+	c.checkInScope(c.level(), func() {
+		if stmt.Key != nil {
+			c.checkLoopVariable(stmt.Key, key)
+		}
+		c.checkLoopVariable(stmt.Value, value)
+		c.checkBlock(stmt.Body)
+	})
+}
+
+func (c *Checker) checkLoopVariable(ident *ast.Identifier, t *Type) {
+	declared := TypeOf(ident.Type)
+	if !declared.Accepts(t) {
+		c.errorf(ident.Pos, "cannot assign %s to %s", t, declared)
+	}
+	c.scope.declare(ident.Name, declared)
+}
+
+// checkCondition checks that exp, the condition of an if, a loop or a
+// logical operator, is a boolean
+func (c *Checker) checkCondition(exp ast.Expression) {
+	if t := c.expr(exp); !Bool.Accepts(t) {
+		c.errorf(exp.Position(), "condition must be %s, got %s", Bool, t)
+	}
+}
+
+// terminates reports whether a function body ending with stmts always
+// ends with a return statement
+func terminates(stmts []ast.Statement) bool {
+	if len(stmts) == 0 {
+		return false
+	}
+
+	switch stmt := stmts[len(stmts)-1].(type) {
+	case *ast.ReturnStatement:
+		return true
+	case *ast.ExpressionStatement:
+		ifExp, ok := stmt.Expression.(*ast.IfExpression)
+		return ok && ifExp.Consequence != nil && ifExp.Alternative != nil &&
+			terminates(ifExp.Consequence.Statements) && terminates(ifExp.Alternative.Statements)
+	case *ast.WhileStatement:
+		cond, ok := stmt.Condition.(*ast.BooleanLiteral)
+		return ok && cond.Value && !breaks(stmt.Body)
+	case *ast.ForStatement:
+		return stmt.Condition == nil && !breaks(stmt.Body)
+	default:
+		return false
+	}
+}
+
+// breaks reports whether block contains a break out of the loop it is
+// the body of
+func breaks(block *ast.BlockStatement) bool {
+	if block == nil {
+		return false
+	}
+
+	for _, stmt := range block.Statements {
+		switch stmt := stmt.(type) {
+		case *ast.BreakStatement:
+			return true
+		case *ast.ExpressionStatement:
+			if ifExp, ok := stmt.Expression.(*ast.IfExpression); ok {
+				if breaks(ifExp.Consequence) || breaks(ifExp.Alternative) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
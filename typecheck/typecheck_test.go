@@ -0,0 +1,227 @@
+package typecheck
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/menxqk/my-interpreter/ast"
+	"github.com/menxqk/my-interpreter/lexer"
+	"github.com/menxqk/my-interpreter/parser"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if p.HasErrors() {
+		t.Fatalf("parse errors for %q: %v", input, p.Errors())
+	}
+	return program
+}
+
+func errorStrings(errs []*Error) []string {
+	out := []string{}
+	for _, err := range errs {
+		out = append(out, err.Error())
+	}
+	return out
+}
+
+func TestCheckValid(t *testing.T) {
+	tests := []string{
+		"int x = 1; x = x + 2; x += 3; x++;",
+		"float f = 1.5; f += 2; f = f * 2;",
+		"string s = \"ab\" + 'c'; s += 'd'; char c = s[0]; string t = s[1:];",
+		"int x; float f = null; dict d;",
+		"int a[] = [1, 2, 3]; a[0] = 4; a[1] *= 2; push(a, 5); int last = pop(a);",
+		"int m[][] = [[1, 2], [3]]; m[0][1] = 9; int n[] = m[1];",
+		"int a[] = [1, 2]; int b[] = a + [3]; int c[] = b - a; a < b;",
+		"dict d = {\"a\": 1, \"b\": [1, 2]}; d[\"c\"] = \"x\"; int x = d[\"a\"]; d = d + {\"e\": 1};",
+		"int add(int a, int b) { return a + b; } int x = add(1, 2);",
+		"int fib(int n) { if (n < 2) { return n; } else { return fib(n - 1) + fib(n - 2); } }",
+		"int f() { return g(); } int g() { return base; } int base = 1;",
+		"int f() { while (true) { return 1; } } int g() { for (;;) { return 2; } }",
+		"int i = 0; while (i < 3) { i++; } for (int j = 0; j < 3; j++) { i += j; }",
+		"int a[] = [1]; for (int i, int v in a) { v += i; } for (char c in \"ab\") { c == 'a'; }",
+		"dict d; for (string k in d) { k += \"x\"; } for (string k, int v in d) { v++; }",
+		"if (true) { int x = 1; } else { int x = 2; } x = 3;",
+		"int x = int(\"1\") + int(1.5) + len(\"abc\") + len([1]); char c = char(65); string s = str(x) + type(x);",
+		"println(1, \"a\", [1]); print(); dict d; bool b = has(d, \"k\"); delete(d, \"k\"); string ks[] = keys(d);",
+		"int x = 1 << 2 | 3 & ~4 ^ 5 >> 1; bool b = !x && (x > 1 || x == 2);",
+		"int len(string s) { return 1; } int x = len(\"abc\");",
+		"dict d; int x = d[\"a\"] + 1; string s = d[\"b\"];",
+		"bool done; bool flags[] = [true, done]; flags[1] = !flags[0]; for (bool f in flags) { done = f; }",
+		"bool even(int n) { return n % 2 == 0; } bool odd(bool e) { return !e; } if (odd(even(3))) { }",
+		"void log(string s) { println(s); } log(\"a\");",
+		"int n; void bump(int by) { if (by < 0) { return; } n += by; } bump(1); if (n > 0) { bump(2); }",
+		"void f() { } for (int i = 0; i < 2; f()) { f(); }",
+		"int apply(func f, int a) { return f(a); } int x = apply(int (int a) { return a * 2; }, 1);",
+		"func adder(int n) { return int (int x) { return x + n; }; } int y = adder(1)(2); func f = adder(3);",
+		"int sq(int a) { return a * a; } func ops[] = [sq, int (int a) { return -a; }]; ops[0] = sq; ops[1](2);",
+		"func none; none = void () { }; none(); int n = int (int a) { return a; }(5);",
+		"return 1;",
+		"return;",
+	}
+
+	for _, input := range tests {
+		program := parse(t, input)
+		if errs := Check(program); len(errs) != 0 {
+			t.Errorf("unexpected errors for %q: %v", input, errorStrings(errs))
+		}
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"int x = \"a\";", []string{"1:9: cannot assign STRING to INT"}},
+		{"int x = 1;\nx = 2.5;", []string{"2:5: cannot assign FLOAT to INT"}},
+		{"int x = 1; x += 1.5;", []string{"1:12: cannot assign FLOAT to INT"}},
+		{"string s = \"a\"; s++;", []string{"1:17: illegal operation STRING + INT"}},
+		{"bool b = 1;", []string{"1:10: cannot assign INT to BOOLEAN"}},
+		{"bool f(bool b) { return 1; }", []string{"1:25: cannot return INT from function returning BOOLEAN"}},
+		{"bool flags[] = [true, 'c'];", []string{"1:23: cannot assign CHAR to BOOLEAN array"}},
+		{"void f() { } int x = f();", []string{"1:22: f() (no value) used as value"}},
+		{"void f() { } println(f());", []string{"1:22: f() (no value) used as value"}},
+		{"void f() { } f() + 1;", []string{"1:14: f() (no value) used as value"}},
+		{"void f() { } int g() { return f(); }", []string{"1:31: f() (no value) used as value"}},
+		{"int f() { return; }", []string{"1:11: missing return value in function returning INT"}},
+		{"func f = 1;", []string{"1:10: cannot assign INT to FUNCTION"}},
+		{"func f = int (int a) { };", []string{"1:10: missing return at the end of function literal"}},
+		{"int x = int (int a) { return a; }(\"s\");", []string{"1:35: wrong type for argument 1 to \"int (int a) { return a; }\", got STRING, expected INT"}},
+		{"int x = bool () { return true; }();", []string{"1:9: cannot assign BOOLEAN to INT"}},
+		{"int x = void () { }();", []string{"1:9: void () { }() (no value) used as value"}},
+		{"int a[] = [1]; a[0](1);", []string{"1:16: \"a[0]\" is not a function, got INT"}},
+		{"int f() { return 1; } int x = f()();", []string{"1:31: \"f()\" is not a function, got INT"}},
+		{"x = 1;", []string{"1:1: \"x\" not declared"}},
+		{"println(y);", []string{"1:9: undeclared name \"y\""}},
+		{"nope(1);", []string{"1:1: undeclared name \"nope\""}},
+		{"int x = y + 1; int y = 2;", []string{"1:9: undeclared name \"y\""}},
+		{"int f(int a) { return a; }\nf(1, 2);", []string{"2:1: wrong number of arguments to \"f\": 2, expected 1"}},
+		{"int f(int a, string b) { return a; }\nf(\"a\", \"b\");", []string{"2:3: wrong type for argument 1 to \"f\", got STRING, expected INT"}},
+		{"int x = 1; x(1);", []string{"1:12: \"x\" is not a function, got INT"}},
+		{"int f(int a) {\n\tif (a > 1) { return 1; }\n}", []string{"1:5: missing return at the end of function \"f\""}},
+		{"int f() { int x = 1; }", []string{"1:5: missing return at the end of function \"f\""}},
+		{"int f() { while (true) { break; } }", []string{"1:5: missing return at the end of function \"f\""}},
+		{"int f() { return \"s\"; }", []string{"1:18: cannot return STRING from function returning INT"}},
+		{"int a[] = [1, 2.0];", []string{"1:15: cannot assign FLOAT to INT array"}},
+		{"int a[2] = [1, 2, 3];", []string{"1:12: 3 elements exceed array capacity 2"}},
+		{"int a[] = 1;", []string{"1:11: cannot assign INT to INT[]"}},
+		{"int m[][] = [[1], ['c']];", []string{"1:20: cannot assign CHAR to INT array"}},
+		{"int a[] = [1]; a[0] = \"s\";", []string{"1:23: cannot assign STRING to INT"}},
+		{"int a[] = [1]; a[\"x\"];", []string{"1:18: array index must be INT, got STRING"}},
+		{"float a[] = [1.0]; push(a, 1);", []string{"1:28: cannot assign INT to FLOAT array"}},
+		{"[1, 'c'];", []string{"1:5: cannot mix INT and CHAR in array"}},
+		{"string s = \"abc\"; s[0] = 'x';", []string{"1:19: cannot assign to STRING index"}},
+		{"dict d; d[1];", []string{"1:11: dict key must be STRING, got INT"}},
+		{"int x = 1; x[0];", []string{"1:12: \"x\" not an array, dict or string, got INT"}},
+		{"int x = 1; x[1:];", []string{"1:12: \"x\" not an array or string, got INT"}},
+		{"string s; s[1.5:];", []string{"1:13: slice index must be INT, got FLOAT"}},
+		{"1 + \"a\";", []string{"1:3: illegal operation INT + STRING"}},
+		{"1.5 & 1;", []string{"1:5: illegal operation FLOAT & INT"}},
+		{"-\"a\";", []string{"1:1: unknown operator: -STRING"}},
+		{"if (1) { 2; }", []string{"1:5: condition must be BOOLEAN, got INT"}},
+		{"while (\"s\") { }", []string{"1:8: condition must be BOOLEAN, got STRING"}},
+		{"1 && true;", []string{"1:1: condition must be BOOLEAN, got INT"}},
+		{"int a[] = [1]; for (string v in a) { }", []string{"1:28: cannot assign INT to STRING"}},
+		{"int x = 1; for (int v in x) { }", []string{"1:26: cannot iterate over INT"}},
+		{"len(1);", []string{"1:5: len: argument must be STRING, ARRAY or DICT, got INT"}},
+		{"len();", []string{"1:1: wrong number of arguments to len: 0, expected 1"}},
+		{"has(1, \"a\");", []string{"1:5: has: argument 1 must be DICT, got INT"}},
+		{"char([1]);", []string{"1:6: char: cannot convert INT[] to CHAR"}},
+		{"for (int i = 0; i < 3; i++) { } i;", []string{"1:33: undeclared name \"i\""}},
+		{"int f(int a) { return a; } a;", []string{"1:28: undeclared name \"a\""}},
+		{
+			"int x = \"a\";\nif (x > 1) {\n\tprintln(z);\n} else {\n\tx = 1.5;\n}",
+			[]string{
+				"1:9: cannot assign STRING to INT",
+				"3:10: undeclared name \"z\"",
+				"5:6: cannot assign FLOAT to INT",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		errs := errorStrings(Check(parse(t, tt.input)))
+		if strings.Join(errs, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("wrong errors for %q\nexpected %v\ngot      %v", tt.input, tt.expected, errs)
+		}
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + 2;", "INT"},
+		{"1 + 2.5;", "FLOAT"},
+		{"'a' + 'b';", "STRING"},
+		{"1 < 2;", "BOOLEAN"},
+		{"[1, 2];", "INT[]"},
+		{"[[1], [2]];", "INT[][]"},
+		{"[];", "ARRAY"},
+		{"\"abc\"[0];", "CHAR"},
+		{"\"abc\"[1:];", "STRING"},
+		{"{\"a\": 1}[\"a\"];", "ANY"},
+		{"keys({});", "STRING[]"},
+		{"float(1);", "FLOAT"},
+		{"null;", "NULL"},
+		{"int (int a, char b) { return a; };", "INT(INT, CHAR)"},
+		{"int (func f) { return f(1); }(int (int a) { return a; });", "INT"},
+	}
+
+	for _, tt := range tests {
+		program := parse(t, tt.input)
+		c := New()
+		if errs := c.Check(program); len(errs) != 0 {
+			t.Fatalf("unexpected errors for %q: %v", tt.input, errorStrings(errs))
+		}
+
+		exp := program.Statements[0].(*ast.ExpressionStatement).Expression
+		if got := c.TypeOf(exp).String(); got != tt.expected {
+			t.Errorf("wrong type for %q, expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	program := parse(t, "int add(int a, float b) { return a; } add;")
+	c := New()
+	c.Check(program)
+	exp := program.Statements[1].(*ast.ExpressionStatement).Expression
+	if got := c.TypeOf(exp).String(); got != "INT(INT, FLOAT)" {
+		t.Errorf("wrong function type, got %s", got)
+	}
+}
+
+func TestDeclare(t *testing.T) {
+	c := New()
+	c.Declare("args", ArrayOf(String))
+	c.Declare("host", Unknown)
+
+	errs := c.Check(parse(t, "string first = args[0]; host(1, 2); int n = host();"))
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errorStrings(errs))
+	}
+
+	errs = c.Check(parse(t, "int first = args[0];"))
+	if len(errs) != 1 || errs[0].Error() != "1:13: cannot assign STRING to INT" {
+		t.Errorf("unexpected errors: %v", errorStrings(errs))
+	}
+
+	// globals declared by a program are known to the next ones
+	c.Check(parse(t, "int counter = 0;"))
+	errs = c.Check(parse(t, "counter++; counter = \"s\";"))
+	if len(errs) != 1 || errs[0].Error() != "1:22: cannot assign STRING to INT" {
+		t.Errorf("unexpected errors: %v", errorStrings(errs))
+	}
+	// unless the program that declared them failed to run
+	c.Check(parse(t, "int failed = 1 / 0;"))
+	c.Rollback()
+	errs = c.Check(parse(t, "failed; counter;"))
+	if len(errs) != 1 || errs[0].Error() != "1:1: undeclared name \"failed\"" {
+		t.Errorf("unexpected errors: %v", errorStrings(errs))
+	}
+}
@@ -0,0 +1,173 @@
+package typecheck
+
+import (
+	"strings"
+
+	"github.com/menxqk/my-interpreter/object"
+)
+
+// ANY is the name of the type of values only known at runtime, like
+// dict elements
+const ANY = object.ANY_OBJ
+
+// VOID is the name of the result type of functions that return nothing
+const VOID = "VOID"
+
+// Type is the static type of an expression, Name being the type of
+// the objects it evaluates to
+type Type struct {
+	Name string
+
+	// element type of arrays
+	Elem *Type
+
+	// parameters and result of functions
+	Params []*Type
+	Result *Type
+}
+
+var (
+	Unknown = &Type{Name: ANY}
+	Int     = &Type{Name: object.INT_OBJ}
+	Float   = &Type{Name: object.FLOAT_OBJ}
+	Char    = &Type{Name: object.CHAR_OBJ}
+	String  = &Type{Name: object.STR_OBJ}
+	Bool    = &Type{Name: object.BOOL_OBJ}
+	Dict    = &Type{Name: object.DICT_OBJ}
+	Null    = &Type{Name: object.NULL_OBJ}
+	Builtin = &Type{Name: object.BUILTIN_OBJ}
+	Void    = &Type{Name: VOID}
+
+	// Func is the type of func variables, functions whose parameters
+	// and result are only known at runtime
+	Func = &Type{Name: object.FN_OBJ}
+)
+
+// ArrayOf returns the type of arrays of elem
+func ArrayOf(elem *Type) *Type {
+	return &Type{Name: object.ARRAY_OBJ, Elem: elem}
+}
+
+// FunctionOf returns the type of functions taking params and returning result
+func FunctionOf(params []*Type, result *Type) *Type {
+	return &Type{Name: object.FN_OBJ, Params: params, Result: result}
+}
+
+// TypeOf returns the type of values declared with the type token
+// typeName, like the element type of an array declaration or the
+// result of a function
+func TypeOf(typeName string) *Type {
+	for _, t := range []*Type{Int, Float, Char, String, Bool, Dict, Void, Func} {
+		if t.Name == typeName {
+			return t
+		}
+	}
+	return Unknown
+}
+
+// TypeOfObject returns the type of obj, a value set by the host program
+func TypeOfObject(obj object.Object) *Type {
+	switch obj := obj.(type) {
+	case *object.Array:
+		// the elements of nested arrays hold their own element type
+		if obj.ArrType == object.ARRAY_OBJ && len(obj.Elements) > 0 {
+			return ArrayOf(TypeOfObject(obj.Elements[0]))
+		}
+		return ArrayOf(TypeOf(obj.ArrType))
+	case *object.Function:
+		if obj.Body == nil {
+			return Func
+		}
+		params := []*Type{}
+		for _, param := range obj.Parameters {
+			params = append(params, TypeOf(param.Type))
+		}
+		return FunctionOf(params, TypeOf(obj.Identifier.Type))
+	default:
+		return TypeOf(obj.Type())
+	}
+}
+
+func (t *Type) String() string {
+	switch t.Name {
+	case object.ARRAY_OBJ:
+		if t.Elem.IsUnknown() {
+			return t.Name
+		}
+		return t.Elem.String() + "[]"
+	case object.FN_OBJ:
+		if !t.HasSignature() {
+			return t.Name
+		}
+		params := []string{}
+		for _, p := range t.Params {
+			params = append(params, p.String())
+		}
+		return t.Result.String() + "(" + strings.Join(params, ", ") + ")"
+	default:
+		return t.Name
+	}
+}
+
+// IsUnknown reports whether values of t are only known at runtime
+func (t *Type) IsUnknown() bool {
+	return t.Name == ANY
+}
+
+// is reports whether t and u are the same type of objects, for types
+// without elements or parameters
+func (t *Type) is(u *Type) bool {
+	return t.Name == u.Name
+}
+
+func (t *Type) IsArray() bool {
+	return t.Name == object.ARRAY_OBJ
+}
+
+func (t *Type) IsFunction() bool {
+	return t.Name == object.FN_OBJ
+}
+
+// HasSignature reports whether the parameters and result of the
+// function type t are known
+func (t *Type) HasSignature() bool {
+	return t.IsFunction() && t.Result != nil
+}
+
+// Accepts reports whether values of type v can be stored where values
+// of t are expected, unknown types accept and are accepted by any type
+func (t *Type) Accepts(v *Type) bool {
+	if t.IsUnknown() || v.IsUnknown() {
+		return true
+	}
+	if t.Name != v.Name {
+		return false
+	}
+
+	switch t.Name {
+	case object.ARRAY_OBJ:
+		return t.Elem.Accepts(v.Elem)
+	case object.FN_OBJ:
+		if !t.HasSignature() || !v.HasSignature() {
+			return true
+		}
+		if len(t.Params) != len(v.Params) || !t.Result.Accepts(v.Result) {
+			return false
+		}
+		for i := range t.Params {
+			if !t.Params[i].Accepts(v.Params[i]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func isNumeric(t *Type) bool {
+	return t.Name == object.INT_OBJ || t.Name == object.FLOAT_OBJ
+}
+
+func isText(t *Type) bool {
+	return t.Name == object.CHAR_OBJ || t.Name == object.STR_OBJ
+}